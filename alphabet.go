@@ -1,13 +1,22 @@
 package enigma
 
 import (
+	"errors"
+	"fmt"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
-// Alphabet is a basic alphabet for all Enigma encodings
+// AlphabetSize is the number of letters of the basic Alphabet
+const AlphabetSize = 26
+
+// Alphabet is the basic alphabet of the Enigma models, custom models can use their own (see ModelDefinition)
 var Alphabet = newAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
+// ErrUnsupportedAlphabet is returned by the packages that only work with the models using the basic Alphabet (see RequireBasicAlphabet)
+var ErrUnsupportedAlphabet = errors.New("only the basic A-Z alphabet is supported")
+
 type alphabet struct {
 	letterMap string
 	indexMap  map[byte]int
@@ -25,11 +34,41 @@ func newAlphabet(alphabetString string) alphabet {
 	}
 }
 
+// parseAlphabet creates a custom alphabet, the basic Alphabet if empty
+func parseAlphabet(alphabetString string) (*alphabet, error) {
+	if alphabetString == "" || alphabetString == Alphabet.letterMap {
+		return &Alphabet, nil
+	}
+	if len(alphabetString) < 2 {
+		return nil, fmt.Errorf("invalid alphabet %s, must have at least 2 letters", alphabetString)
+	}
+	for i := 0; i < len(alphabetString); i++ {
+		letter := alphabetString[i]
+		if letter <= ' ' || letter >= utf8.RuneSelf {
+			return nil, fmt.Errorf("invalid alphabet %s, unsupported letter %q", alphabetString, rune(letter))
+		}
+		if strings.IndexByte(alphabetString[:i], letter) != -1 {
+			return nil, fmt.Errorf("invalid alphabet %s, letter %s is duplicate", alphabetString, string(letter))
+		}
+	}
+	a := newAlphabet(alphabetString)
+	return &a, nil
+}
+
 func (a *alphabet) charToInt(letter byte) (int, bool) {
 	val, ok := a.indexMap[letter]
 	return val, ok
 }
 
+// contains checks if the given character is a letter of the alphabet
+func (a *alphabet) contains(letter rune) bool {
+	if letter >= utf8.RuneSelf {
+		return false
+	}
+	_, ok := a.indexMap[byte(letter)]
+	return ok
+}
+
 func (a *alphabet) intToChar(index int) byte {
 	return a.letterMap[index]
 }
@@ -39,7 +78,7 @@ func (a *alphabet) getSize() int {
 }
 
 func (a *alphabet) isValidWiring(wiring string) bool {
-	return sortString(wiring) == sortString(Alphabet.letterMap)
+	return sortString(wiring) == sortString(a.letterMap)
 }
 
 type sortRunes []rune
@@ -64,59 +103,28 @@ func sortString(s string) string {
 
 // shift moves the given letter (specified by its index) byt the specified amount in the alphabet (Z wraps around back to A)
 // accepts both positive and negative numbers, and it's cyclical (Z wraps around back to A and A back to Z)
-func shift(input, shiftBy int) int {
-	result := (input + shiftBy) % Alphabet.getSize()
+func (a *alphabet) shift(input, shiftBy int) int {
+	result := (input + shiftBy) % a.getSize()
 	if result < 0 {
-		result = Alphabet.getSize() + result
+		result = a.getSize() + result
 	}
 	return result
 }
 
 // getDefaultLetterMap generates mapping of each letter in the alphabet to itself
-func getDefaultLetterMap() map[int]int {
-	letterMap := make(map[int]int, Alphabet.getSize())
-	for i := 0; i < Alphabet.getSize(); i++ {
+func (a *alphabet) getDefaultLetterMap() map[int]int {
+	letterMap := make(map[int]int, a.getSize())
+	for i := 0; i < a.getSize(); i++ {
 		letterMap[i] = i
 	}
 	return letterMap
 }
 
-// these are optimized for english language (the "to" letter pairs almost never occur in common english)
-var substitutions = []struct {
-	from string
-	to   string
-}{
-	{"Q ", "QW "},
-	{" Q", " WQ"},
-	{" ", "QQ"},
-	{"X,", "XW,"},
-	{",X", ",WX"},
-	{",", "XX"},
-	{"V.", "VW."},
-	{".V", ".WV"},
-	{".", "VV"},
-	{"Y-", "YQ-"},
-	{"-Y", "-QY"},
-	{"-", "YY"},
-}
-
-// Preprocess prepares the given text for Enigma encryption.
-// Unifies the text case and handles some common unsupported characters
-func Preprocess(text string) string {
-	text = strings.ToUpper(text) // convert to uppercase
-	// replace punctuations with double letters
-	for _, sub := range substitutions {
-		text = strings.ReplaceAll(text, sub.from, sub.to)
-	}
-
-	return text
-}
-
-// Postprocess converts preprocessed text back to readable format (complementary to Preprocess)
-func Postprocess(text string) string {
-	// convert the punctuations back
-	for i := len(substitutions) - 1; i >= 0; i-- {
-		text = strings.ReplaceAll(text, substitutions[i].to, substitutions[i].from)
+// copyLetterMap creates an independent copy of the given letter mapping
+func copyLetterMap(letterMap map[int]int) map[int]int {
+	result := make(map[int]int, len(letterMap))
+	for from, to := range letterMap {
+		result[from] = to
 	}
-	return text
+	return result
 }
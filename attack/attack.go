@@ -0,0 +1,273 @@
+// Package attack implements the ciphertext-only attack on Enigma, as described by Gillogly and later improved by Weierud and Sullivan.
+//
+// The attack runs in two steps. First all the rotor orders and start positions are tried with the plugboard empty
+// and the decrypts are ranked by the index of coincidence (even partially correct decrypts are closer to the natural language
+// than a random text). Ring settings of the best candidates are then adjusted the same way, and finally the plugboard pairs
+// are found by hill-climbing on bigram and trigram scores of the decrypt (see the scoring package).
+//
+// The attack needs a reasonably long ciphertext (hundreds of letters), the more plugs were used, the longer it has to be.
+package attack
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+	"github.com/tomas-hanicinec/enigma/scoring"
+)
+
+const alphabetSize = enigma.AlphabetSize
+
+// Config specifies the machine and the search space of the attack
+type Config struct {
+	Model       enigma.Model
+	Reflectors  []enigma.ReflectorModel                  // all fixed-wiring reflectors supported by the model if empty
+	RotorOrders []map[enigma.RotorSlot]enigma.RotorModel // all rotor orders supported by the model if empty
+	Candidates  int                                      // number of best rotor positions to search the plugboard for, 10 by default
+	MaxPlugs    int                                      // maximum number of plugboard pairs, 10 by default
+	Language    scoring.Language                         // language of the plaintext, German by default
+	Workers     int                                      // number of CPUs if empty
+}
+
+// Candidate is the recovered machine configuration along with the decrypted text
+type Candidate struct {
+	Settings  enigma.Settings
+	Plaintext string
+	Score     float64 // average trigram log-probability of the plaintext in the configured language, higher is better
+}
+
+type scoredSettings struct {
+	settings enigma.Settings
+	score    float64
+}
+
+// CiphertextOnly recovers the most probable machine settings for the given ciphertext,
+// returns the candidates ordered from the best one
+func CiphertextOnly(config Config, ciphertext string) ([]Candidate, error) {
+	if err := config.setDefaults(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(ciphertext); i++ {
+		if ciphertext[i] < 'A' || ciphertext[i] > 'Z' {
+			return nil, fmt.Errorf("unsupported letter \"%s\" in the ciphertext, only uppercase letters A-Z allowed", string(ciphertext[i]))
+		}
+	}
+
+	// I. rotor orders and start positions
+	best := searchPositions(config, ciphertext)
+
+	// II. ring settings and plugboard
+	candidates := make([]Candidate, len(best))
+	toolkit.RunParallel(config.Workers, len(best), func(i int) {
+		candidates[i] = refine(config, best[i].settings, ciphertext)
+	})
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates, nil
+}
+
+func (c *Config) setDefaults() error {
+	e, err := enigma.NewEnigma(c.Model)
+	if err != nil {
+		return err
+	}
+	if err := c.Model.RequireBasicAlphabet(); err != nil {
+		return err
+	}
+	if len(c.Reflectors) == 0 {
+		for _, reflector := range c.Model.GetAvailableReflectorModels() {
+			if !reflector.IsRewirable() {
+				c.Reflectors = append(c.Reflectors, reflector)
+			}
+		}
+	}
+	if len(c.RotorOrders) == 0 {
+		c.RotorOrders = c.Model.GetRotorOrders()
+	}
+	if c.Candidates <= 0 {
+		c.Candidates = 10
+	}
+	if c.MaxPlugs <= 0 {
+		c.MaxPlugs = 10
+	}
+	if c.Language.Bigrams == nil || c.Language.Trigrams == nil {
+		c.Language = scoring.German
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+
+	// validate the search space
+	for _, order := range c.RotorOrders {
+		if err = e.RotorsSelect(order); err != nil {
+			return fmt.Errorf("invalid rotor order: %w", err)
+		}
+	}
+	for _, reflector := range c.Reflectors {
+		if err = e.ReflectorSelect(reflector); err != nil {
+			return fmt.Errorf("invalid reflector: %w", err)
+		}
+	}
+	return nil
+}
+
+// searchPositions tries all the rotor orders, reflectors and start positions with the empty plugboard,
+// returns the best ones by the index of coincidence of the decrypt
+func searchPositions(config Config, ciphertext string) []scoredSettings {
+	type job struct {
+		order     map[enigma.RotorSlot]enigma.RotorModel
+		reflector enigma.ReflectorConfig
+		first     byte
+	}
+	var jobs []job
+	for _, order := range config.RotorOrders {
+		for _, reflector := range getReflectorConfigs(config.Reflectors) {
+			for letter := 0; letter < alphabetSize; letter++ {
+				jobs = append(jobs, job{order: order, reflector: reflector, first: byte('A' + letter)})
+			}
+		}
+	}
+
+	results := make([][]scoredSettings, len(jobs))
+	toolkit.RunParallel(config.Workers, len(jobs), func(i int) {
+		results[i] = searchJob(config, jobs[i].order, jobs[i].reflector, jobs[i].first, ciphertext)
+	})
+
+	var best []scoredSettings
+	for _, jobResults := range results {
+		for _, result := range jobResults {
+			best = keepBest(best, result, config.Candidates)
+		}
+	}
+	return best
+}
+
+func searchJob(config Config, order map[enigma.RotorSlot]enigma.RotorModel, reflector enigma.ReflectorConfig, first byte, ciphertext string) []scoredSettings {
+	rotors := make(map[enigma.RotorSlot]enigma.RotorConfig, len(order))
+	for slot, model := range order {
+		rotors[slot] = enigma.RotorConfig{Model: model, RingPosition: 1}
+	}
+	e, err := enigma.NewEnigmaWithSetup(config.Model, rotors, reflector, "")
+	if err != nil {
+		panic(fmt.Errorf("failed to create Enigma: %w", err)) // should not happen, the config is validated by setDefaults
+	}
+
+	slots := config.Model.GetAvailableRotorSlots()
+	positions := make([]byte, len(slots))
+	positions[len(slots)-1] = first // leftmost rotor is fixed for the job
+	count := 1
+	for i := 0; i < len(slots)-1; i++ {
+		count *= alphabetSize
+	}
+
+	var best []scoredSettings
+	for n := 0; n < count; n++ {
+		for i, rest := 0, n; i < len(slots)-1; i, rest = i+1, rest/alphabetSize {
+			positions[i] = byte('A' + rest%alphabetSize)
+		}
+		for i, slot := range slots {
+			if err = e.RotorSetWheel(slot, positions[i]); err != nil {
+				panic(fmt.Errorf("failed to set wheel position: %w", err))
+			}
+		}
+		decrypted, err := e.Encode(ciphertext)
+		if err != nil {
+			panic(fmt.Errorf("failed to decrypt: %w", err))
+		}
+
+		score := scoring.IndexOfCoincidence(decrypted)
+		if len(best) < config.Candidates || score > best[len(best)-1].score {
+			best = keepBest(best, scoredSettings{settings: e.Settings(), score: score}, config.Candidates)
+		}
+	}
+	return best
+}
+
+// refine finds the ring settings and the plugboard for the given rotor order and start position
+func refine(config Config, settings enigma.Settings, ciphertext string) Candidate {
+	e, err := enigma.NewEnigmaFromSettings(settings)
+	if err != nil {
+		panic(fmt.Errorf("failed to create Enigma: %w", err))
+	}
+	decrypt := func() string {
+		e.RotorsReset()
+		decrypted, err := e.Encode(ciphertext)
+		if err != nil {
+			panic(fmt.Errorf("failed to decrypt: %w", err))
+		}
+		return decrypted
+	}
+
+	// rings of the right and the middle rotor only change where the next rotor steps,
+	// so the wheel is turned along with the ring to keep the same wiring position
+	for _, slot := range []enigma.RotorSlot{enigma.Right, enigma.Middle} {
+		initialWheel, _ := e.GetRotorInitialWheel(slot)
+		bestRing, bestScore := 1, scoring.IndexOfCoincidence(decrypt())
+		for ring := 2; ring <= alphabetSize; ring++ {
+			setRing(&e, slot, initialWheel, ring)
+			if score := scoring.IndexOfCoincidence(decrypt()); score > bestScore {
+				bestRing, bestScore = ring, score
+			}
+		}
+		setRing(&e, slot, initialWheel, bestRing)
+	}
+
+	if config.Model.HasPlugboard() {
+		plugs := newPlugs()
+		plugs = climbPlugs(&e, plugs, config.MaxPlugs, func() float64 { return config.Language.Bigrams.Score(decrypt()) })
+		climbPlugs(&e, plugs, config.MaxPlugs, func() float64 { return config.Language.Trigrams.Score(decrypt()) })
+	}
+
+	plaintext := decrypt()
+	return Candidate{
+		Settings:  e.Settings(),
+		Plaintext: plaintext,
+		Score:     config.Language.Trigrams.Score(plaintext),
+	}
+}
+
+func setRing(e *enigma.Enigma, slot enigma.RotorSlot, initialWheel byte, ring int) {
+	if err := e.RotorSetRing(slot, ring); err != nil {
+		panic(fmt.Errorf("failed to set ring position: %w", err))
+	}
+	wheel := byte('A' + (int(initialWheel-'A')+ring-1)%alphabetSize)
+	if err := e.RotorSetWheel(slot, wheel); err != nil {
+		panic(fmt.Errorf("failed to set wheel position: %w", err))
+	}
+}
+
+// getReflectorConfigs returns all the configurations of the given reflectors (all positions for movable reflectors)
+func getReflectorConfigs(reflectors []enigma.ReflectorModel) []enigma.ReflectorConfig {
+	var result []enigma.ReflectorConfig
+	for _, reflector := range reflectors {
+		if !reflector.IsMovable() {
+			result = append(result, enigma.ReflectorConfig{Model: reflector})
+			continue
+		}
+		for letter := 0; letter < alphabetSize; letter++ {
+			result = append(result, enigma.ReflectorConfig{Model: reflector, WheelPosition: byte('A' + letter)})
+		}
+	}
+	return result
+}
+
+// keepBest inserts the item to the list ordered by score (best first), keeping at most the given number of items
+func keepBest(list []scoredSettings, item scoredSettings, size int) []scoredSettings {
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].score < item.score
+	})
+	if i >= size {
+		return list
+	}
+	list = append(list, scoredSettings{})
+	copy(list[i+1:], list[i:])
+	list[i] = item
+	if len(list) > size {
+		list = list[:size]
+	}
+	return list
+}
@@ -0,0 +1,81 @@
+package attack
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestCiphertextOnly(t *testing.T) {
+	plaintext := strings.ToUpper(strings.Join(strings.Fields(`
+		Die Gruppe meldet starke Feindbewegungen im Westen der Stadt und bittet um sofortige Unterstuetzung durch die Artillerie
+		Die Verbindung zur Nachbardivision ist seit heute Morgen unterbrochen und die Funkstelle versucht die Lage zu klaeren
+		Der Angriff soll bei Einbruch der Dunkelheit beginnen wenn die Panzer ihre Bereitstellung erreicht haben
+		Alle Kompanien haben sich um zehn Uhr am Waldrand zu sammeln und weitere Befehle des Kommandeurs abzuwarten
+		Munition und Verpflegung werden in der Nacht nach vorne gebracht und an die Einheiten verteilt
+	`), ""))
+
+	settings, err := enigma.ParseSettings("M3 B II-IV-I 01-01-14 QFD AR CT EL GN")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	e, err := enigma.NewEnigmaFromSettings(settings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	ciphertext, err := e.Encode(plaintext)
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+
+	candidates, err := CiphertextOnly(Config{
+		Model:       enigma.M3,
+		Reflectors:  []enigma.ReflectorModel{enigma.UkwB},
+		RotorOrders: []map[enigma.RotorSlot]enigma.RotorModel{{enigma.Left: enigma.RotorII, enigma.Middle: enigma.RotorIV, enigma.Right: enigma.RotorI}},
+		Candidates:  3,
+	}, ciphertext)
+	if err != nil {
+		t.Errorf("attack error = %v", err)
+		return
+	}
+	if len(candidates) == 0 {
+		t.Errorf("no candidates found")
+		return
+	}
+
+	best := candidates[0]
+	if best.Plaintext != plaintext {
+		t.Errorf("best candidate %s\nwant = %v\n got = %v", best.Settings, plaintext, best.Plaintext)
+	}
+
+	// the candidate settings must produce the same plaintext
+	recovered, err := enigma.NewEnigmaFromSettings(best.Settings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	if decrypted, _ := recovered.Encode(ciphertext); decrypted != best.Plaintext {
+		t.Errorf("candidate settings do not decrypt the ciphertext\nwant = %v\n got = %v", best.Plaintext, decrypted)
+	}
+}
+
+func TestCiphertextOnly_InvalidConfig(t *testing.T) {
+	if _, err := CiphertextOnly(Config{Model: "M5"}, "ABC"); err == nil {
+		t.Errorf("expected model error, got none")
+	}
+	if _, err := CiphertextOnly(Config{Model: enigmatest.RegisterNumeric()}, "0123456789"); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+	if _, err := CiphertextOnly(Config{Model: enigma.M3, Reflectors: []enigma.ReflectorModel{enigma.UkwK}}, "ABC"); err == nil {
+		t.Errorf("expected reflector error, got none")
+	}
+	if _, err := CiphertextOnly(Config{Model: enigma.M3}, "abc"); err == nil {
+		t.Errorf("expected ciphertext error, got none")
+	}
+}
@@ -0,0 +1,84 @@
+package attack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+// plugs represents the plugboard as a letter mapping (unplugged letters map to themselves)
+type plugs [alphabetSize]int
+
+func newPlugs() plugs {
+	p := plugs{}
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+func (p plugs) count() int {
+	count := 0
+	for i, mapped := range p {
+		if mapped > i {
+			count++
+		}
+	}
+	return count
+}
+
+// toggle connects the two letters (disconnecting their current partners) or disconnects them if already connected
+func (p plugs) toggle(a, b int) plugs {
+	if p[a] == b {
+		p[a], p[b] = a, b
+		return p
+	}
+	for _, letter := range []int{a, b} {
+		partner := p[letter]
+		p[letter], p[partner] = letter, partner
+	}
+	p[a], p[b] = b, a
+	return p
+}
+
+func (p plugs) String() string {
+	pairs := make([]string, 0, alphabetSize/2)
+	for i, mapped := range p {
+		if mapped > i {
+			pairs = append(pairs, string([]byte{byte('A' + i), byte('A' + mapped)}))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
+
+// climbPlugs hill-climbs the plugboard configuration - tries toggling every letter pair and keeps the change if it
+// improves the score, until there is no improvement. The best plugs are left set up on the machine
+func climbPlugs(e *enigma.Enigma, current plugs, maxPlugs int, score func() float64) plugs {
+	setPlugs(e, current)
+	best := score()
+	for improved := true; improved; {
+		improved = false
+		for a := 0; a < alphabetSize; a++ {
+			for b := a + 1; b < alphabetSize; b++ {
+				candidate := current.toggle(a, b)
+				if candidate.count() > maxPlugs {
+					continue
+				}
+				setPlugs(e, candidate)
+				if candidateScore := score(); candidateScore > best {
+					current, best = candidate, candidateScore
+					improved = true
+				}
+			}
+		}
+	}
+	setPlugs(e, current)
+	return current
+}
+
+func setPlugs(e *enigma.Enigma, p plugs) {
+	if err := e.PlugboardSetup(p.String()); err != nil {
+		panic(fmt.Errorf("failed to set up plugboard: %w", err))
+	}
+}
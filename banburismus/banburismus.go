@@ -0,0 +1,126 @@
+// Package banburismus implements the Bletchley Park Banburismus procedure against the naval Enigma traffic
+// (message keys enciphered at the ground setting of the day, post-1940 procedure).
+//
+// The first letters of the enciphered message keys (indicators) are enciphered at the same machine positions for all the messages
+// of the day, so messages with indicators differing only in the last letter were enciphered with the same positions of all
+// but the right rotor. Sliding such messages against each other, the right offset puts them "in depth" and the letters
+// repeat more often than by chance (the repeats are weighted in decibans). The offsets are then chained together
+// (scritchmus) to find the relative positions of the right rotor, and the middle rotor turnovers breaking the depths
+// tell which rotor is the right one, cutting down the rotor orders the bombe has to test.
+//
+// The naval indicators were further disguised by the bigram tables, the messages passed here must have the tables
+// already stripped off.
+package banburismus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tomas-hanicinec/enigma/scoring"
+)
+
+// Config specifies the scoring of the overlaps
+type Config struct {
+	Language   scoring.Language // language of the plaintext, German by default
+	Threshold  float64          // minimum score of an alignment in decibans, 20 by default
+	MinOverlap int              // minimum number of overlapping letters, 50 by default
+}
+
+// Alignment is the best scoring offset of two messages with the same indicators (except the last letter)
+type Alignment struct {
+	First, Second int     // indexes of the messages
+	Offset        int     // position of the right rotor of the second message minus the position of the first one
+	Repeats       int     // number of repeated letters in the overlap
+	Overlap       int     // number of overlapping letters
+	Score         float64 // odds of the messages being in depth at this offset in decibans
+}
+
+// FindAlignments scores all the offsets of the message pairs sharing all but the last indicator letter and returns
+// the best alignments scoring above the threshold, ordered from the highest score
+func FindAlignments(config Config, messages []Message) ([]Alignment, error) {
+	if config.Language.Monograms == nil {
+		config.Language = scoring.German
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = 20
+	}
+	if config.MinOverlap <= 0 {
+		config.MinOverlap = 50
+	}
+	kappa := config.Language.Kappa()
+
+	groups := map[string][]int{}
+	var groupKeys []string
+	for i, message := range messages {
+		if len(message.Indicator) < 2 {
+			return nil, fmt.Errorf("invalid indicator \"%s\" of message %d", message.Indicator, i)
+		}
+		for j := 0; j < len(message.Ciphertext); j++ {
+			if message.Ciphertext[j] < 'A' || message.Ciphertext[j] > 'Z' {
+				return nil, fmt.Errorf("unsupported letter \"%s\" in message %d, only uppercase letters A-Z allowed", string(message.Ciphertext[j]), i)
+			}
+		}
+		key := message.Indicator[:len(message.Indicator)-1]
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	var result []Alignment
+	for _, key := range groupKeys {
+		group := groups[key]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				first, second := messages[group[i]], messages[group[j]]
+				if first.Indicator == second.Indicator {
+					continue // same message key, nothing to align
+				}
+				best := Alignment{First: group[i], Second: group[j], Score: math.Inf(-1)}
+				for offset := -(alphabetSize - 1); offset < alphabetSize; offset++ {
+					if offset == 0 {
+						continue
+					}
+					repeats, overlap, score := ScoreOverlap(first.Ciphertext, second.Ciphertext, offset, kappa)
+					if overlap >= config.MinOverlap && score > best.Score {
+						best.Offset, best.Repeats, best.Overlap, best.Score = offset, repeats, overlap, score
+					}
+				}
+				if best.Score >= config.Threshold {
+					result = append(result, best)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
+
+// ScoreOverlap counts the repeats of the two ciphertexts with the second one shifted by the given offset (the letter i
+// of the second ciphertext under the letter i+offset of the first one) and scores them in decibans - the odds of the texts
+// being in depth against being random, given the kappa (repeat probability) of the plaintext language
+func ScoreOverlap(first, second string, offset int, kappa float64) (repeats, overlap int, score float64) {
+	start, end := 0, len(second)
+	if offset < 0 {
+		start = -offset
+	}
+	if len(first)-offset < end {
+		end = len(first) - offset
+	}
+	for i := start; i < end; i++ {
+		if first[i+offset] == second[i] {
+			repeats++
+		}
+	}
+	if end > start {
+		overlap = end - start
+	}
+
+	random := 1.0 / alphabetSize
+	score = 10*float64(repeats)*math.Log10(kappa/random) + 10*float64(overlap-repeats)*math.Log10((1-kappa)/(1-random))
+	return repeats, overlap, score
+}
@@ -0,0 +1,129 @@
+package banburismus
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestScoreOverlap(t *testing.T) {
+	tests := []struct {
+		name          string
+		first, second string
+		offset        int
+		repeats       int
+		overlap       int
+	}{
+		{"no offset", "ABCDE", "ABXDE", 0, 4, 5},
+		{"positive offset", "XXABCD", "ABCD", 2, 4, 4},
+		{"negative offset", "ABCD", "XXABCDYY", -2, 4, 4},
+		{"no overlap", "ABC", "ABC", 5, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repeats, overlap, score := ScoreOverlap(tt.first, tt.second, tt.offset, 0.07)
+			if repeats != tt.repeats || overlap != tt.overlap {
+				t.Errorf("want = %d/%d\n got = %d/%d", tt.repeats, tt.overlap, repeats, overlap)
+			}
+			if repeats > 0 && score <= 0 {
+				t.Errorf("repeats should score positive, got %v", score)
+			}
+		})
+	}
+}
+
+func TestBanburismus(t *testing.T) {
+	settings, err := enigma.ParseSettings("M3 B II-IV-V 05-12-19 AT BG DV EW FR HN IQ JX KZ LU")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	messages, err := GenerateTraffic(TrafficConfig{Settings: settings, Ground: "QRS", Messages: 600}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Errorf("traffic error = %v", err)
+		return
+	}
+
+	// generated traffic must decrypt with the message keys
+	e, _ := enigma.NewEnigmaFromSettings(settings)
+	for _, slot := range []enigma.RotorSlot{enigma.Left, enigma.Middle, enigma.Right} {
+		_ = e.RotorSetWheel(slot, "QRS"[2-int(slot)])
+	}
+	if key, _ := e.Encode(messages[0].Indicator); key != messages[0].Key {
+		t.Errorf("want = %v\n got = %v", messages[0].Key, key)
+	}
+
+	alignments, err := FindAlignments(Config{}, messages)
+	if err != nil {
+		t.Errorf("alignments error = %v", err)
+		return
+	}
+	if len(alignments) == 0 {
+		t.Errorf("no alignments found")
+		return
+	}
+	correct := 0
+	for _, alignment := range alignments {
+		if int(messages[alignment.Second].Key[2])-int(messages[alignment.First].Key[2]) == alignment.Offset {
+			correct++
+		}
+	}
+	if correct < len(alignments)*3/4 {
+		t.Errorf("only %d of %d alignments correct", correct, len(alignments))
+	}
+
+	chains := BuildChains(messages, alignments)
+	rotors, err := InferRightRotor(enigma.M3, chains)
+	if err != nil {
+		t.Errorf("infer error = %v", err)
+		return
+	}
+	// an occasional wrong alignment can slip into the chains, but the true rotor must still be a clear winner
+	if rotors[0].Model != enigma.RotorV || rotors[0].Penalty*4 >= rotors[1].Penalty {
+		t.Errorf("right rotor V not inferred, got %s (penalty %v), next %s (penalty %v)", rotors[0].Model, rotors[0].Penalty, rotors[1].Model, rotors[1].Penalty)
+		return
+	}
+	found := rotors[0]
+
+	// the true message keys must be among the consistent chain placements
+	keys := map[byte]byte{}
+	for _, message := range messages {
+		keys[message.Indicator[2]] = message.Key[2]
+	}
+	for i, chain := range chains {
+		ok := false
+		for _, placement := range found.Keys[i] {
+			match := true
+			for letter, key := range placement {
+				match = match && keys[letter] == key
+			}
+			ok = ok || match
+		}
+		if !ok {
+			t.Errorf("true keys not among the placements of chain %s", chain)
+		}
+	}
+
+	orders := FilterRotorOrders(enigma.M3.GetRotorOrders(), rotors[:1])
+	if got, want := len(orders), len(enigma.M3.GetRotorOrders())/8; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
+
+func TestBanburismus_Alphabet(t *testing.T) {
+	enigmatest.RegisterNumeric()
+	settings, err := enigma.ParseSettings(enigmatest.NumericSettings)
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	if _, err = GenerateTraffic(TrafficConfig{Settings: settings, Ground: "123", Messages: 10}, rand.New(rand.NewSource(1))); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+	if _, err = InferRightRotor(enigmatest.RegisterNumeric(), nil); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+}
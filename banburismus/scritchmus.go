@@ -0,0 +1,231 @@
+package banburismus
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+// Chain is a set of the last indicator letters with known relative positions of the right rotor in their message keys
+type Chain struct {
+	Positions map[byte]int // indicator letter -> right rotor position relative to the first letter of the chain
+	edges     []edge
+}
+
+// edge is the offset of the right rotor between the message keys of two indicator letters
+type edge struct {
+	from, to byte
+	offset   int
+	score    float64
+}
+
+// RightRotor is the right rotor hypothesis, along with the best assignments of the message key letters to the chains
+type RightRotor struct {
+	Model   enigma.RotorModel
+	Penalty float64           // sum of the scores of the alignments contradicting the rotor, 0 if consistent with all of them
+	Keys    [][]map[byte]byte // for each chain the assignments of the indicator letters to the key letters with the lowest penalty
+}
+
+// BuildChains links the last indicator letters of the aligned messages to chains (scritchmus), alignments are processed
+// from the highest score and those contradicting the already built chains are dropped
+func BuildChains(messages []Message, alignments []Alignment) []Chain {
+	sorted := append([]Alignment(nil), alignments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+
+	var chains []*Chain
+	find := func(letter byte) *Chain {
+		for _, chain := range chains {
+			if _, ok := chain.Positions[letter]; ok {
+				return chain
+			}
+		}
+		return nil
+	}
+	for _, alignment := range sorted {
+		first, second := messages[alignment.First].Indicator, messages[alignment.Second].Indicator
+		e := edge{from: first[len(first)-1], to: second[len(second)-1], offset: alignment.Offset, score: alignment.Score}
+		from, to := find(e.from), find(e.to)
+		if from != nil && from == to {
+			if mod(from.Positions[e.from]+e.offset) == from.Positions[e.to] {
+				from.edges = append(from.edges, e)
+			}
+			continue
+		}
+		if from == nil {
+			from = &Chain{Positions: map[byte]int{e.from: 0}}
+		}
+		if to == nil {
+			to = &Chain{Positions: map[byte]int{e.to: 0}}
+		}
+
+		// merge the chain of the second letter into the chain of the first one, unless the letters collide
+		// or no placement of the merged chain is reciprocal
+		merged := &Chain{Positions: make(map[byte]int, len(from.Positions)+len(to.Positions))}
+		for letter, position := range from.Positions {
+			merged.Positions[letter] = position
+		}
+		shift := from.Positions[e.from] + e.offset - to.Positions[e.to]
+		ok := true
+		for letter, position := range to.Positions {
+			ok = ok && merged.add(letter, mod(position+shift))
+		}
+		if !ok || !merged.hasReciprocalPlacement() {
+			continue
+		}
+		merged.edges = append(append(append(merged.edges, from.edges...), to.edges...), e)
+
+		var rest []*Chain
+		for _, chain := range chains {
+			if chain != from && chain != to {
+				rest = append(rest, chain)
+			}
+		}
+		chains = append(rest, merged)
+	}
+
+	result := make([]Chain, len(chains))
+	for i, chain := range chains {
+		result[i] = *chain
+	}
+	return result
+}
+
+// add places the letter to the chain, fails if the position is already taken
+func (c *Chain) add(letter byte, position int) bool {
+	for _, taken := range c.Positions {
+		if taken == position {
+			return false
+		}
+	}
+	c.Positions[letter] = position
+	return true
+}
+
+func (c *Chain) hasReciprocalPlacement() bool {
+	for shift := 0; shift < alphabetSize; shift++ {
+		if isReciprocal(c.getPlacement(shift)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPlacement assigns the key letters to the chain letters with the chain start on the given position
+func (c Chain) getPlacement(shift int) map[byte]byte {
+	keys := make(map[byte]byte, len(c.Positions))
+	for letter, position := range c.Positions {
+		keys[letter] = byte('A' + mod(position+shift))
+	}
+	return keys
+}
+
+// String writes the chain the Bletchley way - letters on their relative positions, gaps marked by dashes
+func (c Chain) String() string {
+	row := []byte(strings.Repeat("-", alphabetSize))
+	for letter, position := range c.Positions {
+		row[position] = letter
+	}
+	return strings.TrimRight(string(row), "-")
+}
+
+// GetKeys returns the assignments of the chain letters to the message key letters fitting the given right rotor best,
+// along with the penalty - the sum of the scores of the alignments contradicting them. An enciphered letter is never the same
+// as the key letter and the encipherment is reciprocal, the keys must follow the offsets without wrapping around
+// and no aligned messages can be separated by a turnover of the middle rotor (the depth would be broken)
+func (c Chain) GetKeys(rotor enigma.RotorModel) ([]map[byte]byte, float64) {
+	notches := rotor.GetNotchPositions()
+	var result []map[byte]byte
+	best := math.Inf(1)
+	for shift := 0; shift < alphabetSize; shift++ {
+		keys := c.getPlacement(shift)
+		if !isReciprocal(keys) {
+			continue
+		}
+		penalty := c.getPenalty(keys, notches)
+		if penalty < best {
+			result, best = nil, penalty
+		}
+		if penalty == best {
+			result = append(result, keys)
+		}
+	}
+	return result, best
+}
+
+func isReciprocal(keys map[byte]byte) bool {
+	for letter, key := range keys {
+		if key == letter {
+			return false
+		}
+		if reverse, ok := keys[key]; ok && reverse != letter {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Chain) getPenalty(keys map[byte]byte, notches []byte) float64 {
+	penalty := 0.0
+	for _, e := range c.edges {
+		from, to := int(keys[e.from]), int(keys[e.to])
+		if from+e.offset != to {
+			penalty += e.score
+			continue
+		}
+		low, high := from, to
+		if low > high {
+			low, high = high, low
+		}
+		for _, notch := range notches {
+			if int(notch) >= low && int(notch) < high {
+				penalty += e.score // the message starting earlier turned the middle rotor before the overlap
+				break
+			}
+		}
+	}
+	return penalty
+}
+
+// InferRightRotor scores all the right rotors of the model against the chains, returns them ordered from the lowest penalty
+// (a few alignments can be wrong, so the rotors are not eliminated outright)
+func InferRightRotor(model enigma.Model, chains []Chain) ([]RightRotor, error) {
+	if err := model.RequireBasicAlphabet(); err != nil {
+		return nil, err
+	}
+	var result []RightRotor
+	for _, rotor := range model.GetAvailableRotorModels(enigma.Right) {
+		candidate := RightRotor{Model: rotor}
+		for _, chain := range chains {
+			keys, penalty := chain.GetKeys(rotor)
+			candidate.Keys = append(candidate.Keys, keys)
+			candidate.Penalty += penalty
+		}
+		result = append(result, candidate)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Penalty < result[j].Penalty
+	})
+	return result, nil
+}
+
+// FilterRotorOrders keeps only the rotor orders with one of the given (best scoring) right rotors, to be tested by the bombe
+func FilterRotorOrders(orders []map[enigma.RotorSlot]enigma.RotorModel, rotors []RightRotor) []map[enigma.RotorSlot]enigma.RotorModel {
+	var result []map[enigma.RotorSlot]enigma.RotorModel
+	for _, order := range orders {
+		for _, rotor := range rotors {
+			if order[enigma.Right] == rotor.Model {
+				result = append(result, order)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func mod(value int) int {
+	return (value%alphabetSize + alphabetSize) % alphabetSize
+}
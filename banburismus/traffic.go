@@ -0,0 +1,99 @@
+package banburismus
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+	"github.com/tomas-hanicinec/enigma/scoring"
+)
+
+const alphabetSize = enigma.AlphabetSize
+
+// TrafficConfig specifies the day key and the messages of the generated traffic
+type TrafficConfig struct {
+	Settings  enigma.Settings  // day key, wheel positions are ignored
+	Ground    string           // ground setting the message keys are enciphered at, one letter per rotor (left to right)
+	Messages  int              // number of messages
+	MinLength int              // minimum message length, 100 by default
+	MaxLength int              // maximum message length, 250 by default
+	Language  scoring.Language // plaintext is made of random words of the language sample, German by default
+}
+
+// Message is a single enciphered message of the day
+type Message struct {
+	Indicator  string // message key enciphered at the ground setting
+	Key        string // message key in clear (known for the generated traffic only)
+	Ciphertext string
+}
+
+// GenerateTraffic enciphers random messages on the given day key, every message with a random message key
+func GenerateTraffic(config TrafficConfig, random *rand.Rand) ([]Message, error) {
+	if err := config.Settings.Model.RequireBasicAlphabet(); err != nil {
+		return nil, err
+	}
+	if config.MinLength <= 0 {
+		config.MinLength = 100
+	}
+	if config.MaxLength <= 0 {
+		config.MaxLength = 250
+	}
+	if config.MaxLength < config.MinLength {
+		return nil, fmt.Errorf("invalid message length range %d-%d", config.MinLength, config.MaxLength)
+	}
+	if config.Language.Sample == "" {
+		config.Language = scoring.German
+	}
+	words := getWords(config.Language.Sample)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("no words in the %s sample", config.Language.Name)
+	}
+
+	e, err := enigma.NewEnigmaFromSettings(config.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day key: %w", err)
+	}
+	slots := toolkit.GetSlots(config.Settings.Model)
+	if len(config.Ground) != len(slots) {
+		return nil, fmt.Errorf("invalid ground setting \"%s\", must have %d letters", config.Ground, len(slots))
+	}
+
+	messages := make([]Message, config.Messages)
+	for i := range messages {
+		key := make([]byte, len(slots))
+		for j := range key {
+			key[j] = byte('A' + random.Intn(alphabetSize))
+		}
+		if err = toolkit.SetWheels(&e, slots, config.Ground); err != nil {
+			return nil, fmt.Errorf("invalid ground setting: %w", err)
+		}
+		indicator, err := e.Encode(string(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encipher the message key: %w", err)
+		}
+
+		length := config.MinLength + random.Intn(config.MaxLength-config.MinLength+1)
+		var plaintext strings.Builder
+		for plaintext.Len() < length {
+			plaintext.WriteString(words[random.Intn(len(words))])
+		}
+		if err = toolkit.SetWheels(&e, slots, string(key)); err != nil {
+			return nil, err
+		}
+		ciphertext, err := e.Encode(plaintext.String()[:length])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encipher the message: %w", err)
+		}
+		messages[i] = Message{Indicator: indicator, Key: string(key), Ciphertext: ciphertext}
+	}
+	return messages, nil
+}
+
+// getWords splits the sample text to words of uppercase letters A-Z
+func getWords(sample string) []string {
+	return strings.FieldsFunc(strings.ToUpper(sample), func(r rune) bool {
+		return r < 'A' || r > 'Z'
+	})
+}
@@ -0,0 +1,203 @@
+// Package bombe simulates the Turing-Welchman Bombe used at Bletchley Park to find the Enigma settings from a crib.
+//
+// The crib (a guessed piece of plaintext) placed against the ciphertext forms a menu - a graph of letters connected
+// by the scramblers (unsteckered Enigmas) at the corresponding message positions. The bombe then tries all rotor orders
+// and start positions, and stops whenever the current position is consistent with some Stecker (plugboard) hypothesis.
+// Each stop is then checked by decoding the crib with an Enigma machine built from it.
+//
+// Same as the original machine the bombe assumes all the rings on the first position, so it only finds the correct
+// start position if there is no middle-rotor turnover in the menu part of the message caused by different ring setting.
+package bombe
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+)
+
+const alphabetSize = enigma.AlphabetSize
+
+// Config specifies the machine and the search space of the bombe run
+type Config struct {
+	Model       enigma.Model
+	Reflector   enigma.ReflectorModel                    // default reflector of the model if empty
+	RotorOrders []map[enigma.RotorSlot]enigma.RotorModel // all rotor orders supported by the model if empty
+	Workers     int                                      // number of CPUs if empty
+}
+
+// Stop is the position where the bombe stopped along with the implied Stecker pairs
+type Stop struct {
+	Settings enigma.Settings // rotor order, start position (with rings on the first position), reflector and the implied plugboard
+	Steckers string          // Stecker pairs implied by the menu (unsteckered letters are not included)
+	Verified bool            // the crib was successfully decoded by an Enigma built from this stop
+}
+
+// Run runs the bombe with the given menu on all the configured rotor orders and start positions
+func Run(config Config, menu Menu) ([]Stop, error) {
+	if err := config.Model.RequireBasicAlphabet(); err != nil {
+		return nil, err
+	}
+	if config.Reflector == "" {
+		e, err := enigma.NewEnigma(config.Model)
+		if err != nil {
+			return nil, err
+		}
+		config.Reflector = e.GetReflectorModel()
+	}
+	if len(config.RotorOrders) == 0 {
+		config.RotorOrders = config.Model.GetRotorOrders()
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	// validate the configuration before starting the run
+	for _, order := range config.RotorOrders {
+		if _, err := newScrambler(config, order); err != nil {
+			return nil, err
+		}
+	}
+
+	// split the work by rotor orders and positions of the leftmost rotor
+	results := make([][]Stop, len(config.RotorOrders)*alphabetSize)
+	toolkit.RunParallel(config.Workers, len(results), func(i int) {
+		results[i] = runJob(config, menu, config.RotorOrders[i/alphabetSize], byte('A'+i%alphabetSize))
+	})
+
+	var stops []Stop
+	for _, jobStops := range results {
+		stops = append(stops, jobStops...)
+	}
+	return stops, nil
+}
+
+func runJob(config Config, menu Menu, order map[enigma.RotorSlot]enigma.RotorModel, first byte) []Stop {
+	e, err := newScrambler(config, order)
+	if err != nil {
+		panic(fmt.Errorf("failed to create scrambler: %w", err)) // should not happen, all the rotor orders are validated by Run
+	}
+	graph := newMenuGraph(menu)
+	slots := config.Model.GetAvailableRotorSlots()
+	positions := make([]byte, len(slots))
+	positions[len(slots)-1] = first // leftmost rotor is fixed for the job
+
+	var stops []Stop
+	count := 1
+	for i := 0; i < len(slots)-1; i++ {
+		count *= alphabetSize
+	}
+	for n := 0; n < count; n++ {
+		// odometer over all the other rotors
+		for i, rest := 0, n; i < len(slots)-1; i, rest = i+1, rest/alphabetSize {
+			positions[i] = byte('A' + rest%alphabetSize)
+		}
+		for i, slot := range slots {
+			if err = e.RotorSetWheel(slot, positions[i]); err != nil {
+				panic(fmt.Errorf("failed to set wheel position: %w", err))
+			}
+		}
+
+		steckers, ok := graph.test(getScramblers(&e, menu))
+		if !ok {
+			continue
+		}
+		stops = append(stops, newStop(config, menu, order, slots, positions, steckers))
+	}
+	return stops
+}
+
+// newScrambler creates the unsteckered Enigma used as the bombe scrambler (all rings on the first position)
+func newScrambler(config Config, order map[enigma.RotorSlot]enigma.RotorModel) (enigma.Enigma, error) {
+	rotors := make(map[enigma.RotorSlot]enigma.RotorConfig, len(order))
+	for slot, model := range order {
+		rotors[slot] = enigma.RotorConfig{Model: model}
+	}
+	e, err := enigma.NewEnigmaWithSetup(config.Model, rotors, enigma.ReflectorConfig{Model: config.Reflector}, "")
+	if err != nil {
+		return enigma.Enigma{}, fmt.Errorf("invalid bombe configuration: %w", err)
+	}
+	return e, nil
+}
+
+// getScramblers computes the scrambler permutations for all the menu edges, starting from the current machine position
+func getScramblers(e *enigma.Enigma, menu Menu) [][alphabetSize]int {
+	if menu.offset > 0 {
+		if _, err := e.Encode(strings.Repeat("A", menu.offset)); err != nil {
+			panic(fmt.Errorf("failed to step the scrambler: %w", err))
+		}
+	}
+
+	scramblers := make([][alphabetSize]int, len(menu.edges))
+	for i := range scramblers {
+		state := e.State()
+		known := [alphabetSize]bool{}
+		for letter := 0; letter < alphabetSize; letter++ {
+			if known[letter] {
+				continue // scrambler is an involution, the pair is already known
+			}
+			if err := e.Restore(state); err != nil {
+				panic(fmt.Errorf("failed to restore the scrambler: %w", err))
+			}
+			encoded, err := e.Encode(string(rune('A' + letter)))
+			if err != nil {
+				panic(fmt.Errorf("failed to encode with the scrambler: %w", err))
+			}
+			mapped := int(encoded[0] - 'A')
+			scramblers[i][letter], scramblers[i][mapped] = mapped, letter
+			known[letter], known[mapped] = true, true
+		}
+		// the machine is now one step further, ready for the next edge
+		if err := e.Restore(state); err != nil {
+			panic(fmt.Errorf("failed to restore the scrambler: %w", err))
+		}
+		if _, err := e.Encode("A"); err != nil {
+			panic(fmt.Errorf("failed to step the scrambler: %w", err))
+		}
+	}
+	return scramblers
+}
+
+func newStop(config Config, menu Menu, order map[enigma.RotorSlot]enigma.RotorModel, slots []enigma.RotorSlot, positions []byte, steckers string) Stop {
+	rotors := make(map[enigma.RotorSlot]enigma.RotorConfig, len(slots))
+	for i, slot := range slots {
+		rotors[slot] = enigma.RotorConfig{Model: order[slot], WheelPosition: positions[i], RingPosition: 1}
+	}
+	stop := Stop{
+		Settings: enigma.Settings{
+			Model:     config.Model,
+			Rotors:    rotors,
+			Reflector: enigma.ReflectorConfig{Model: config.Reflector},
+		},
+		Steckers: steckers,
+	}
+	if config.Model.HasPlugboard() {
+		stop.Settings.Plugboard = steckers
+	}
+	stop.Verified = verify(stop, menu)
+	return stop
+}
+
+// verify decodes the menu part of the message with an Enigma built from the stop and compares it with the crib
+func verify(stop Stop, menu Menu) bool {
+	if !stop.Settings.Model.HasPlugboard() && stop.Steckers != "" {
+		return false
+	}
+	e, err := enigma.NewEnigmaFromSettings(stop.Settings)
+	if err != nil {
+		return false
+	}
+
+	ciphertext := make([]byte, 0, menu.getEnd())
+	ciphertext = append(ciphertext, strings.Repeat("A", menu.offset)...) // just to step the rotors
+	for _, edge := range menu.edges {
+		ciphertext = append(ciphertext, edge.Cipher)
+	}
+	decoded, err := e.Encode(string(ciphertext))
+	if err != nil {
+		return false
+	}
+	return decoded[menu.offset:] == menu.crib
+}
@@ -0,0 +1,147 @@
+package bombe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestNewMenu(t *testing.T) {
+	menu, err := NewMenu("QWERTZUIOPASDF", "WETTER", 3)
+	if err != nil {
+		t.Errorf("menu error = %v", err)
+		return
+	}
+	if got := len(menu.GetEdges()); got != 6 {
+		t.Errorf("edges want = %v, got = %v", 6, got)
+	}
+	if got := menu.GetCentralLetter(); got != 'T' {
+		t.Errorf("central letter want = %v, got = %v", "T", string(got))
+	}
+
+	if _, err = NewMenu("QWERTZUIOPASDF", "WETTER", 2); err == nil {
+		t.Errorf("expected error for letter encoding to itself, got none")
+	}
+	if _, err = NewMenu("QWERTZ", "WETTER", 1); err == nil {
+		t.Errorf("expected error for crib out of the ciphertext, got none")
+	}
+}
+
+func TestRun(t *testing.T) {
+	settings, err := enigma.ParseSettings("M3 B II-V-III 01-01-01 KDR AM BT CQ DV EI FX GJ HS KN LR")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	e, err := enigma.NewEnigmaFromSettings(settings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	plaintext := "XXWETTERVORHERSAGEBISKAYAXXX"
+	ciphertext, err := e.Encode(plaintext)
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+
+	menu, err := NewMenu(ciphertext, "WETTERVORHERSAGE", 2)
+	if err != nil {
+		t.Errorf("menu error = %v", err)
+		return
+	}
+	stops, err := Run(Config{
+		Model:       enigma.M3,
+		Reflector:   enigma.UkwB,
+		RotorOrders: []map[enigma.RotorSlot]enigma.RotorModel{{enigma.Left: enigma.RotorII, enigma.Middle: enigma.RotorV, enigma.Right: enigma.RotorIII}},
+	}, menu)
+	if err != nil {
+		t.Errorf("bombe error = %v", err)
+		return
+	}
+
+	found := false
+	for _, stop := range stops {
+		if !stop.Verified {
+			continue
+		}
+		if strings.HasPrefix(stop.Settings.String(), "M3 B II-V-III 01-01-01 KDR") {
+			found = true
+			candidate, err := enigma.NewEnigmaFromSettings(stop.Settings)
+			if err != nil {
+				t.Errorf("config error = %v", err)
+				return
+			}
+			decoded, _ := candidate.Encode(ciphertext)
+			if decoded[2:18] != "WETTERVORHERSAGE" {
+				t.Errorf("crib not decoded by the stop, got = %v", decoded)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("correct position not found in %d stops", len(stops))
+	}
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	menu, _ := NewMenu("QWERTZUIOPASDF", "WETTER", 2)
+	_, err := Run(Config{
+		Model:       enigma.M3,
+		RotorOrders: []map[enigma.RotorSlot]enigma.RotorModel{{enigma.Left: enigma.RotorII, enigma.Middle: enigma.RotorII, enigma.Right: enigma.RotorIII}},
+	}, menu)
+	if err == nil {
+		t.Errorf("expected configuration error, got none")
+	}
+	if _, err = Run(Config{Model: enigmatest.RegisterNumeric()}, menu); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+}
+
+func TestFindCribPositions(t *testing.T) {
+	positions := FindCribPositions("QWERTZUIOPASDF", "WETTER")
+	want := map[int]bool{0: true, 3: true, 4: true, 5: true, 6: true, 7: true, 8: true}
+	if len(positions) != len(want) {
+		t.Errorf("positions want = %v, got = %v", len(want), positions)
+	}
+	if positions[0].Offset != 0 || positions[0].Loops != 1 {
+		t.Errorf("best position want = offset 0 with 1 loop, got = %v", positions[0])
+	}
+	for i, position := range positions {
+		if !want[position.Offset] {
+			t.Errorf("invalid crib position %d", position.Offset)
+		}
+		if i > 0 && position.Score > positions[i-1].Score {
+			t.Errorf("positions not ordered by score: %v", positions)
+		}
+	}
+}
+
+func TestMenu_GetLoops(t *testing.T) {
+	tests := []struct {
+		name       string
+		ciphertext string
+		crib       string
+		want       int
+	}{
+		{name: "no loop", ciphertext: "BCDE", crib: "ABCD", want: 0},            // A-B-C-D-E chain
+		{name: "one loop", ciphertext: "BCA", crib: "ABC", want: 1},             // A-B-C-A triangle
+		{name: "two loops", ciphertext: "BCAC", crib: "ABCA", want: 2},          // triangle with extra A-C edge
+		{name: "separate loops", ciphertext: "BCAEFD", crib: "ABCDEF", want: 2}, // two triangles
+		{name: "repeated pair", ciphertext: "BXA", crib: "AYB", want: 1},        // A-B twice
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			menu, err := NewMenu(tt.ciphertext, tt.crib, 0)
+			if err != nil {
+				t.Errorf("menu error = %v", err)
+				return
+			}
+			if got := menu.GetLoops(); got != tt.want {
+				t.Errorf("want = %v, got = %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package bombe
+
+import (
+	"sort"
+)
+
+// CribPosition is a possible position of the crib in the ciphertext along with the quality of its menu
+type CribPosition struct {
+	Offset int
+	Loops  int     // number of independent loops in the menu letter graph, more loops means fewer false stops
+	Score  float64 // integer part is the number of loops, fraction is the share of crib letters in the largest connected part of the menu
+}
+
+// FindCribPositions slides the crib along the ciphertext and returns all the offsets where no letter would encode to itself
+// (which Enigma can never do), ordered from the best menu to the worst
+func FindCribPositions(ciphertext, crib string) []CribPosition {
+	var result []CribPosition
+	for offset := 0; offset+len(crib) <= len(ciphertext); offset++ {
+		menu, err := NewMenu(ciphertext, crib, offset)
+		if err != nil {
+			continue // letter encoding to itself (or unsupported letter)
+		}
+		loops := menu.GetLoops()
+		result = append(result, CribPosition{
+			Offset: offset,
+			Loops:  loops,
+			Score:  float64(loops) + float64(menu.getLargestComponentSize())/float64(len(crib)+1),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result
+}
+
+// GetLoops returns the number of independent loops (closed cycles) in the menu letter graph
+func (m Menu) GetLoops() int {
+	letters, components := m.getComponents()
+	return len(m.edges) - letters + components
+}
+
+// getComponents returns the number of distinct letters in the menu and the number of its connected parts
+func (m Menu) getComponents() (int, int) {
+	parents := m.getComponentParents()
+	letters, components := 0, 0
+	for letter, parent := range parents {
+		if parent == -1 {
+			continue
+		}
+		letters++
+		if parent == letter {
+			components++
+		}
+	}
+	return letters, components
+}
+
+// getLargestComponentSize returns the number of edges in the largest connected part of the menu
+func (m Menu) getLargestComponentSize() int {
+	parents := m.getComponentParents()
+	sizes := map[int]int{}
+	largest := 0
+	for _, edge := range m.edges {
+		root := findRoot(parents, int(edge.Plain-'A'))
+		sizes[root]++
+		if sizes[root] > largest {
+			largest = sizes[root]
+		}
+	}
+	return largest
+}
+
+// getComponentParents runs union-find over the menu letters, letters not present in the menu have parent -1
+func (m Menu) getComponentParents() [alphabetSize]int {
+	parents := [alphabetSize]int{}
+	for i := range parents {
+		parents[i] = -1
+	}
+	for _, edge := range m.edges {
+		a, b := int(edge.Plain-'A'), int(edge.Cipher-'A')
+		for _, letter := range []int{a, b} {
+			if parents[letter] == -1 {
+				parents[letter] = letter
+			}
+		}
+		rootA, rootB := findRoot(parents, a), findRoot(parents, b)
+		if rootA != rootB {
+			parents[rootB] = rootA
+		}
+	}
+	// flatten, so that every letter points directly to the root of its component
+	for i := range parents {
+		if parents[i] != -1 {
+			parents[i] = findRoot(parents, i)
+		}
+	}
+	return parents
+}
+
+func findRoot(parents [alphabetSize]int, letter int) int {
+	for parents[letter] != letter {
+		letter = parents[letter]
+	}
+	return letter
+}
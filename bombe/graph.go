@@ -0,0 +1,90 @@
+package bombe
+
+import (
+	"strings"
+)
+
+// menuGraph is the electrical representation of the menu - 26 registers (one per menu letter) of 26 wires each,
+// wire (x, y) being live means the hypothesis "x is steckered to y"
+type menuGraph struct {
+	central   int
+	adjacency [alphabetSize][]menuLink
+}
+
+type menuLink struct {
+	letter int // letter on the other side of the scrambler
+	edge   int // index of the scrambler
+}
+
+func newMenuGraph(menu Menu) menuGraph {
+	graph := menuGraph{central: int(menu.GetCentralLetter() - 'A')}
+	for i, edge := range menu.edges {
+		plain, cipher := int(edge.Plain-'A'), int(edge.Cipher-'A')
+		graph.adjacency[plain] = append(graph.adjacency[plain], menuLink{letter: cipher, edge: i})
+		graph.adjacency[cipher] = append(graph.adjacency[cipher], menuLink{letter: plain, edge: i})
+	}
+	return graph
+}
+
+// test energizes the test register with the given scramblers and determines if the bombe stops.
+// The bombe stops when the test register is not fully live (either just one wire, or all but one wire is live),
+// returns the Stecker pairs implied by the stop or false if there is no stop or the implications are contradictory
+func (g *menuGraph) test(scramblers [][alphabetSize]int) (string, bool) {
+	live, counts := g.energize(scramblers, g.central, 0)
+	hypothesis := 0
+	switch counts[g.central] {
+	case 1:
+		// the first wire is the right one
+	case alphabetSize - 1:
+		// the only dead wire is the right one
+		for y := 0; y < alphabetSize; y++ {
+			if !live[g.central*alphabetSize+y] {
+				hypothesis = y
+				break
+			}
+		}
+		live, counts = g.energize(scramblers, g.central, hypothesis)
+	default:
+		return "", false
+	}
+
+	// consistent hypothesis implies exactly one Stecker partner for every letter reached
+	pairs := make([]string, 0, alphabetSize/2)
+	for x := 0; x < alphabetSize; x++ {
+		if counts[x] > 1 {
+			return "", false
+		}
+		for y := x + 1; y < alphabetSize; y++ {
+			if live[x*alphabetSize+y] {
+				pairs = append(pairs, string([]byte{byte('A' + x), byte('A' + y)}))
+			}
+		}
+	}
+	return strings.Join(pairs, " "), true
+}
+
+// energize puts voltage on the given wire and returns all the wires it spreads to, along with live wire count per register
+func (g *menuGraph) energize(scramblers [][alphabetSize]int, register, wire int) ([alphabetSize * alphabetSize]bool, [alphabetSize]int) {
+	live := [alphabetSize * alphabetSize]bool{}
+	counts := [alphabetSize]int{}
+	queue := make([]int, 0, alphabetSize*alphabetSize)
+
+	activate := func(x, y int) {
+		if !live[x*alphabetSize+y] {
+			live[x*alphabetSize+y] = true
+			counts[x]++
+			queue = append(queue, x*alphabetSize+y)
+		}
+	}
+	activate(register, wire)
+	for len(queue) > 0 {
+		x, y := queue[0]/alphabetSize, queue[0]%alphabetSize
+		queue = queue[1:]
+
+		activate(y, x) // diagonal board - x steckered to y means y steckered to x
+		for _, link := range g.adjacency[x] {
+			activate(link.letter, scramblers[link.edge][y])
+		}
+	}
+	return live, counts
+}
@@ -0,0 +1,88 @@
+package bombe
+
+import (
+	"fmt"
+)
+
+// Edge connects a crib letter with the corresponding ciphertext letter, Offset is the position in the message
+type Edge struct {
+	Plain  byte
+	Cipher byte
+	Offset int
+}
+
+// Menu is the letter graph built from a crib placed against the ciphertext,
+// every crib letter is connected to its ciphertext letter by an edge labelled with its position in the message
+type Menu struct {
+	crib   string
+	offset int
+	edges  []Edge
+}
+
+// NewMenu builds the menu from the ciphertext and the crib placed at the given offset of the ciphertext
+func NewMenu(ciphertext, crib string, offset int) (Menu, error) {
+	if crib == "" {
+		return Menu{}, fmt.Errorf("empty crib")
+	}
+	if offset < 0 || offset+len(crib) > len(ciphertext) {
+		return Menu{}, fmt.Errorf("crib of length %d does not fit into ciphertext of length %d at offset %d", len(crib), len(ciphertext), offset)
+	}
+
+	edges := make([]Edge, len(crib))
+	for i := 0; i < len(crib); i++ {
+		plain, cipher := crib[i], ciphertext[offset+i]
+		if !isLetter(plain) || !isLetter(cipher) {
+			return Menu{}, fmt.Errorf("unsupported letter at position %d, only uppercase letters A-Z allowed", offset+i)
+		}
+		if plain == cipher {
+			return Menu{}, fmt.Errorf("invalid crib position, letter %s would encode to itself at position %d", string(plain), offset+i)
+		}
+		edges[i] = Edge{Plain: plain, Cipher: cipher, Offset: offset + i}
+	}
+
+	return Menu{
+		crib:   crib,
+		offset: offset,
+		edges:  edges,
+	}, nil
+}
+
+// GetEdges returns all the edges of the menu
+func (m Menu) GetEdges() []Edge {
+	return m.edges
+}
+
+// GetOffset returns the position of the crib in the ciphertext
+func (m Menu) GetOffset() int {
+	return m.offset
+}
+
+// GetCrib returns the crib the menu was built from
+func (m Menu) GetCrib() string {
+	return m.crib
+}
+
+// GetCentralLetter returns the most connected letter of the menu, the test register of the bombe is connected to it
+func (m Menu) GetCentralLetter() byte {
+	degrees := [alphabetSize]int{}
+	for _, edge := range m.edges {
+		degrees[edge.Plain-'A']++
+		degrees[edge.Cipher-'A']++
+	}
+	central := 0
+	for i := range degrees {
+		if degrees[i] > degrees[central] {
+			central = i
+		}
+	}
+	return byte('A' + central)
+}
+
+// getEnd returns the position in the message right after the last menu letter
+func (m Menu) getEnd() int {
+	return m.offset + len(m.crib)
+}
+
+func isLetter(letter byte) bool {
+	return letter >= 'A' && letter <= 'Z'
+}
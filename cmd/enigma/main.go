@@ -0,0 +1,240 @@
+// Command enigma encodes text with a configurable Enigma machine.
+//
+// Usage:
+//
+//	enigma [flags] [file ...]
+//
+// The text is read from the given files (or from the standard input if there are none) and the result is written
+// to the standard output. Every line is encoded separately, but the rotors keep turning over the whole input,
+// so the output can be decoded by running it through the same command again.
+//
+// Example:
+//
+//	echo "Some text to encode" | enigma -model M3 -rotors left:I:A:1,middle:II:B:1,right:III:C:1 -reflector B -plugboard "AB CD" -preprocess
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+var schemes = map[string]enigma.Scheme{
+	"default":         enigma.DefaultScheme,
+	"wehrmacht":       enigma.WehrmachtScheme,
+	"kriegsmarine":    enigma.KriegsmarineScheme,
+	"transliteration": enigma.TransliterationScheme,
+	"lossless":        enigma.LosslessScheme,
+}
+
+type options struct {
+	model           string
+	rotors          string
+	reflector       string
+	reflectorWheel  string
+	reflectorWiring string
+	plugboard       string
+	preprocess      bool
+	postprocess     bool
+	scheme          string
+	passThrough     bool
+	output          string
+	listModels      bool
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.model, "model", string(enigma.M3), "Enigma model, use -models to list all supported ones")
+	flag.StringVar(&opts.rotors, "rotors", "", "comma separated rotor configuration in the slot:model:wheel:ring format (ie. left:I:A:1,middle:II:B:1,right:III:C:1), wheel and ring are optional")
+	flag.StringVar(&opts.reflector, "reflector", "", "reflector model")
+	flag.StringVar(&opts.reflectorWheel, "reflector-wheel", "", "reflector wheel position (only for movable reflectors)")
+	flag.StringVar(&opts.reflectorWiring, "reflector-wiring", "", "reflector wiring as space separated letter pairs (only for rewirable UKW-D reflectors)")
+	flag.StringVar(&opts.plugboard, "plugboard", "", "plugboard configuration as space separated letter pairs (ie. \"AB CD EF\")")
+	flag.BoolVar(&opts.preprocess, "preprocess", false, "preprocess the input text before encoding (handles case, spaces and basic punctuation)")
+	flag.BoolVar(&opts.postprocess, "postprocess", false, "postprocess the encoded text to make the decoded output more readable")
+	flag.BoolVar(&opts.passThrough, "pass-through", false, "keep the unsupported characters (spaces, digits, punctuation) in place instead of failing")
+	flag.StringVar(&opts.scheme, "scheme", "default", "preprocessing scheme used by -preprocess and -postprocess (default, wehrmacht, kriegsmarine, transliteration or lossless)")
+	flag.StringVar(&opts.output, "o", "", "output file (standard output by default)")
+	flag.BoolVar(&opts.listModels, "models", false, "list all supported Enigma models and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\n\nEncodes text from the given files (or standard input) with the configured Enigma machine.\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(opts, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "enigma: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts options, files []string) error {
+	if opts.listModels {
+		for _, model := range enigma.GetSupportedModels() {
+			fmt.Printf("%s\t%s\n", model, model.GetName())
+		}
+		return nil
+	}
+
+	e, err := createEnigma(opts)
+	if err != nil {
+		return err
+	}
+	scheme, ok := schemes[opts.scheme]
+	if !ok {
+		return fmt.Errorf("unsupported preprocessing scheme %s", opts.scheme)
+	}
+
+	input, err := readInput(files)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := encode(&e, opts, scheme, input)
+	if err != nil {
+		return err
+	}
+	if opts.output == "" {
+		if _, err = io.WriteString(os.Stdout, encoded); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+	return writeFile(opts.output, encoded)
+}
+
+// encode encodes the input line by line, the whole output is kept in memory so nothing is written on failure
+func encode(e *enigma.Enigma, opts options, scheme enigma.Scheme, input string) (string, error) {
+	var encodeOptions []enigma.EncodeOption
+	if opts.passThrough {
+		encodeOptions = append(encodeOptions, enigma.PassThrough())
+	}
+
+	var builder strings.Builder
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(input, "\r\n", "\n"), "\n"), "\n")
+	for i, line := range lines {
+		if opts.preprocess {
+			line = enigma.PreprocessWith(scheme, line)
+		}
+		encoded, err := e.Encode(line, encodeOptions...)
+		if err != nil {
+			return "", fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if opts.postprocess {
+			encoded = enigma.PostprocessWith(scheme, encoded)
+		}
+		builder.WriteString(encoded)
+		builder.WriteByte('\n')
+	}
+	return builder.String(), nil
+}
+
+// writeFile writes to a temporary file first and only replaces the output file once everything is written
+func writeFile(path, content string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".enigma-*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(file.Name()) // no-op after the successful rename
+
+	if _, err = file.WriteString(content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	return nil
+}
+
+func createEnigma(opts options) (enigma.Enigma, error) {
+	rotors, err := parseRotors(opts.rotors)
+	if err != nil {
+		return enigma.Enigma{}, err
+	}
+
+	reflector := enigma.ReflectorConfig{
+		Model:  enigma.ReflectorModel(opts.reflector),
+		Wiring: opts.reflectorWiring,
+	}
+	if opts.reflectorWheel != "" {
+		if len(opts.reflectorWheel) != 1 {
+			return enigma.Enigma{}, fmt.Errorf("invalid reflector wheel position \"%s\", must be a single letter", opts.reflectorWheel)
+		}
+		reflector.WheelPosition = opts.reflectorWheel[0]
+	}
+
+	return enigma.NewEnigmaWithSetup(enigma.Model(opts.model), rotors, reflector, opts.plugboard)
+}
+
+func parseRotors(config string) (map[enigma.RotorSlot]enigma.RotorConfig, error) {
+	result := map[enigma.RotorSlot]enigma.RotorConfig{}
+	if config == "" {
+		return result, nil
+	}
+
+	for _, rotorString := range strings.Split(config, ",") {
+		parts := strings.Split(strings.TrimSpace(rotorString), ":")
+		if len(parts) < 2 || len(parts) > 4 {
+			return nil, fmt.Errorf("invalid rotor configuration \"%s\", must be in the slot:model:wheel:ring format", rotorString)
+		}
+
+		var slot enigma.RotorSlot
+		if err := slot.UnmarshalText([]byte(parts[0])); err != nil {
+			return nil, err
+		}
+		if _, ok := result[slot]; ok {
+			return nil, fmt.Errorf("rotor slot %s configured twice", parts[0])
+		}
+
+		rotorConfig := enigma.RotorConfig{Model: enigma.RotorModel(parts[1])}
+		if len(parts) > 2 && parts[2] != "" {
+			if len(parts[2]) != 1 {
+				return nil, fmt.Errorf("invalid wheel position \"%s\" for rotor %s, must be a single letter", parts[2], parts[1])
+			}
+			rotorConfig.WheelPosition = parts[2][0]
+		}
+		if len(parts) > 3 && parts[3] != "" {
+			ring, err := strconv.Atoi(parts[3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ring position \"%s\" for rotor %s, must be a number", parts[3], parts[1])
+			}
+			rotorConfig.RingPosition = ring
+		}
+		result[slot] = rotorConfig
+	}
+
+	return result, nil
+}
+
+func readInput(files []string) (string, error) {
+	if len(files) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read standard input: %w", err)
+		}
+		return string(data), nil
+	}
+
+	var builder strings.Builder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read input file: %w", err)
+		}
+		builder.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			builder.WriteByte('\n')
+		}
+	}
+	return builder.String(), nil
+}
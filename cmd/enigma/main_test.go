@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+func TestParseRotors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    map[enigma.RotorSlot]enigma.RotorConfig
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			config: "",
+			want:   map[enigma.RotorSlot]enigma.RotorConfig{},
+		},
+		{
+			name:   "full",
+			config: "left:I:A:1, middle:II:B:2,right:III:C:26",
+			want: map[enigma.RotorSlot]enigma.RotorConfig{
+				enigma.Left:   {Model: enigma.RotorI, WheelPosition: 'A', RingPosition: 1},
+				enigma.Middle: {Model: enigma.RotorII, WheelPosition: 'B', RingPosition: 2},
+				enigma.Right:  {Model: enigma.RotorIII, WheelPosition: 'C', RingPosition: 26},
+			},
+		},
+		{
+			name:   "optional wheel and ring",
+			config: "fourth:beta,right:VIII::5,left:III-K:Q",
+			want: map[enigma.RotorSlot]enigma.RotorConfig{
+				enigma.Fourth: {Model: enigma.RotorBeta},
+				enigma.Right:  {Model: enigma.RotorVIII, RingPosition: 5},
+				enigma.Left:   {Model: enigma.RotorIIIK, WheelPosition: 'Q'},
+			},
+		},
+		{name: "missing model", config: "left", wantErr: true},
+		{name: "too many parts", config: "left:I:A:1:2", wantErr: true},
+		{name: "unknown slot", config: "top:I", wantErr: true},
+		{name: "slot twice", config: "left:I,left:II", wantErr: true},
+		{name: "long wheel", config: "left:I:AB", wantErr: true},
+		{name: "invalid ring", config: "left:I:A:x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRotors(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("want error = %v\n got error = %v", tt.wantErr, err)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want = %v\n got = %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRun_Output(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(input, []byte("HELLO\nWORLD\n"), 0o600); err != nil {
+		t.Errorf("write error = %v", err)
+		return
+	}
+
+	// successful run writes the whole output, running it through again decodes it
+	output := filepath.Join(dir, "output.txt")
+	decoded := filepath.Join(dir, "decoded.txt")
+	opts := options{model: string(enigma.M3), scheme: "default"}
+	for _, files := range [][2]string{{input, output}, {output, decoded}} {
+		opts.output = files[1]
+		if err := run(opts, []string{files[0]}); err != nil {
+			t.Errorf("run error = %v", err)
+			return
+		}
+	}
+	data, err := os.ReadFile(decoded)
+	if err != nil {
+		t.Errorf("read error = %v", err)
+		return
+	}
+	if got, want := string(data), "HELLO\nWORLD\n"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+
+	// failed encoding must not leave any output behind
+	if err = os.WriteFile(input, []byte("HELLO\nWORLD 42\n"), 0o600); err != nil {
+		t.Errorf("write error = %v", err)
+		return
+	}
+	opts.output = filepath.Join(dir, "failed.txt")
+	if err = run(opts, []string{input}); err == nil {
+		t.Errorf("expected encode error, got none")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Errorf("unexpected files left in the output directory: %v", entries)
+	}
+}
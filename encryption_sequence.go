@@ -8,6 +8,7 @@ import (
 // EncryptionSequence contains detailed information about encryption process of a single letter,
 // can be used for debugging via Enigma.EncodeVerbose
 type EncryptionSequence struct {
+	alphabet       *alphabet
 	rotorPositions []int
 	in             int
 	out            int
@@ -19,7 +20,13 @@ type encryptionStep struct {
 	out   int
 }
 
-func (es *EncryptionSequence) start(rotors []rotor, letterToEncrypt int) {
+// the recording methods are no-op on nil sequence, so the encoding does not have to check for that
+
+func (es *EncryptionSequence) start(rotors []rotor, letterToEncrypt int, a *alphabet) {
+	if es == nil {
+		return
+	}
+	es.alphabet = a
 	es.in = letterToEncrypt
 	es.rotorPositions = make([]int, len(rotors))
 	for i := range rotors {
@@ -28,6 +35,9 @@ func (es *EncryptionSequence) start(rotors []rotor, letterToEncrypt int) {
 }
 
 func (es *EncryptionSequence) addStep(title string, encodedLetter int) {
+	if es == nil {
+		return
+	}
 	step := encryptionStep{
 		title: title,
 		out:   encodedLetter,
@@ -35,13 +45,23 @@ func (es *EncryptionSequence) addStep(title string, encodedLetter int) {
 	es.steps = append(es.steps, step)
 }
 
+func (es *EncryptionSequence) addRotorStep(rotorIndex int, encodedLetter int) {
+	if es == nil {
+		return
+	}
+	es.addStep(fmt.Sprintf("rotor %d", rotorIndex+1), encodedLetter)
+}
+
 func (es *EncryptionSequence) finish(encodedLetter int) {
+	if es == nil {
+		return
+	}
 	es.out = encodedLetter
 }
 
 // GetResult returns the final encrypted letter
 func (es *EncryptionSequence) GetResult() byte {
-	return Alphabet.intToChar(es.out)
+	return es.alphabet.intToChar(es.out)
 }
 
 // Format returns human-readable string representation of the sequence
@@ -49,14 +69,14 @@ func (es *EncryptionSequence) Format() string {
 	separator := "---------------------------------\n"
 	positions := make([]string, len(es.rotorPositions))
 	for i, position := range es.rotorPositions {
-		positions[len(es.rotorPositions)-i-1] = string(Alphabet.intToChar(position))
+		positions[len(es.rotorPositions)-i-1] = string(es.alphabet.intToChar(position))
 	}
-	result := fmt.Sprintf("INPUT: %s\n", string(Alphabet.intToChar(es.in)))
+	result := fmt.Sprintf("INPUT: %s\n", string(es.alphabet.intToChar(es.in)))
 	result += fmt.Sprintf("rotor wheel positions: %s\n", strings.Join(positions, ", "))
 	for _, step := range es.steps {
-		result += fmt.Sprintf("%s: %s\n", step.title, string(Alphabet.intToChar(step.out)))
+		result += fmt.Sprintf("%s: %s\n", step.title, string(es.alphabet.intToChar(step.out)))
 	}
-	result += fmt.Sprintf("OUTPUT: %s\n", string(Alphabet.intToChar(es.out)))
+	result += fmt.Sprintf("OUTPUT: %s\n", string(es.alphabet.intToChar(es.out)))
 
 	return separator + result + separator
 }
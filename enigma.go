@@ -2,6 +2,8 @@ package enigma
 
 import (
 	"fmt"
+	"strings"
+	"unicode"
 )
 
 // Enigma represents the whole Enigma machine
@@ -11,6 +13,8 @@ type Enigma struct {
 	entryWheel etw
 	rotors     []rotor
 	reflector  reflector
+	stepping   Stepping
+	alphabet   *alphabet
 }
 
 // RotorSlot represents the slot for the rotor. Most Enigmas had three
@@ -24,17 +28,54 @@ const (
 	Fourth RotorSlot = 3
 )
 
+var rotorSlotNames = map[RotorSlot]string{
+	Right:  "right",
+	Middle: "middle",
+	Left:   "left",
+	Fourth: "fourth",
+}
+
+// String returns the name of the rotor slot (right, middle, left or fourth)
+func (s RotorSlot) String() string {
+	if name, ok := rotorSlotNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("RotorSlot(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler, rotor slots are marshalled by their names
+func (s RotorSlot) MarshalText() ([]byte, error) {
+	if _, ok := rotorSlotNames[s]; !ok {
+		return nil, fmt.Errorf("unsupported rotor slot %d", s)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepts the rotor slot names (case-insensitive)
+func (s *RotorSlot) UnmarshalText(text []byte) error {
+	for slot, name := range rotorSlotNames {
+		if strings.EqualFold(name, string(text)) {
+			*s = slot
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported rotor slot \"%s\", must be one of right, middle, left or fourth", string(text))
+}
+
 // NewEnigma creates the given Enigma machine model with the default settings (usually everything on "zero" position)
 func NewEnigma(model Model) (Enigma, error) {
 	if !model.exists() {
 		return Enigma{}, fmt.Errorf("unsupported model %s", model)
 	}
+	a := model.getAlphabet()
 	e := Enigma{
 		Model:      model,
-		plugboard:  newPlugboard(model.HasPlugboard()),
-		entryWheel: newEtw(model.getEtwWiring()),
+		plugboard:  newPlugboard(model.HasPlugboard(), a),
+		entryWheel: newEtw(model.getEtwWiring(), a),
 		rotors:     []rotor{},
-		reflector:  newReflector(model.getDefaultReflectorModel()),
+		reflector:  newReflector(model.getDefaultReflectorModel(), a),
+		stepping:   model.GetStepping(),
+		alphabet:   a,
 	}
 
 	// select default rotors to all the slots
@@ -73,11 +114,110 @@ func NewEnigmaWithSetup(model Model, rotors map[RotorSlot]RotorConfig, reflector
 	return e, nil
 }
 
+// Clone creates a fully independent copy of this Enigma machine (including the current rotor positions).
+// Copying the Enigma value directly is not enough as the copies would share the rotors and other internal state
+func (e *Enigma) Clone() Enigma {
+	rotors := make([]rotor, len(e.rotors))
+	for i := range e.rotors {
+		rotors[i] = e.rotors[i].clone()
+	}
+	return Enigma{
+		Model:      e.Model,
+		plugboard:  e.plugboard.clone(),
+		entryWheel: e.entryWheel.clone(),
+		rotors:     rotors,
+		reflector:  e.reflector.clone(),
+		stepping:   e.stepping,
+		alphabet:   e.alphabet,
+	}
+}
+
+// Settings returns the snapshot of the full configuration of this Enigma machine.
+// Rotors are captured with their starting wheel positions (the ones RotorsReset goes back to),
+// so the snapshot can be used to create an identical machine via NewEnigmaFromSettings
+func (e *Enigma) Settings() Settings {
+	rotors := make(map[RotorSlot]RotorConfig, len(e.rotors))
+	for i := range e.rotors {
+		rotors[e.rotorIndexToSlot(i)] = RotorConfig{
+			Model:         e.rotors[i].model,
+			WheelPosition: e.rotors[i].getInitialWheelPosition(),
+			RingPosition:  e.rotors[i].getRingPosition(),
+		}
+	}
+
+	reflector := ReflectorConfig{Model: e.reflector.model}
+	if e.reflector.model.IsMovable() {
+		reflector.WheelPosition = e.reflector.getInitialWheelPosition()
+	}
+	if e.reflector.model.IsRewirable() {
+		reflector.Wiring = e.reflector.getWiring()
+	}
+
+	return Settings{
+		Model:     e.Model,
+		Rotors:    rotors,
+		Reflector: reflector,
+		Plugboard: e.GetPlugboard(),
+	}
+}
+
+// GetRotorModel returns the rotor model placed in the given slot
+func (e *Enigma) GetRotorModel(slot RotorSlot) (RotorModel, error) {
+	if !e.HasRotorSlot(slot) {
+		return "", fmt.Errorf("unsupported rotor slot %d", slot)
+	}
+	return e.rotors[e.rotorSlotToIndex(slot)].model, nil
+}
+
+// GetRotorWheel returns the current wheel position (rotation) of the given rotor
+func (e *Enigma) GetRotorWheel(slot RotorSlot) (byte, error) {
+	if !e.HasRotorSlot(slot) {
+		return 0, fmt.Errorf("unsupported rotor slot %d", slot)
+	}
+	return e.alphabet.intToChar(e.rotors[e.rotorSlotToIndex(slot)].getWheelPosition()), nil
+}
+
+// GetRotorInitialWheel returns the starting wheel position of the given rotor (the one RotorsReset goes back to)
+func (e *Enigma) GetRotorInitialWheel(slot RotorSlot) (byte, error) {
+	if !e.HasRotorSlot(slot) {
+		return 0, fmt.Errorf("unsupported rotor slot %d", slot)
+	}
+	return e.rotors[e.rotorSlotToIndex(slot)].getInitialWheelPosition(), nil
+}
+
+// GetRotorRing returns the ring setting (ringstellung) of the given rotor
+func (e *Enigma) GetRotorRing(slot RotorSlot) (int, error) {
+	if !e.HasRotorSlot(slot) {
+		return 0, fmt.Errorf("unsupported rotor slot %d", slot)
+	}
+	return e.rotors[e.rotorSlotToIndex(slot)].getRingPosition(), nil
+}
+
 // GetReflectorModel returns the reflector model currently placed in this Enigma machine
 func (e *Enigma) GetReflectorModel() ReflectorModel {
 	return e.reflector.model
 }
 
+// GetReflectorWheel returns the wheel position of the reflector (fixed reflectors are always on the first position)
+func (e *Enigma) GetReflectorWheel() byte {
+	return e.reflector.getWheelPosition()
+}
+
+// GetReflectorInitialWheel returns the starting wheel position of the reflector (only differs from the current one on stepping reflectors)
+func (e *Enigma) GetReflectorInitialWheel() byte {
+	return e.reflector.getInitialWheelPosition()
+}
+
+// GetReflectorWiring returns the current wiring of a rewirable reflector as letter pairs (empty for other reflectors)
+func (e *Enigma) GetReflectorWiring() string {
+	return e.reflector.getWiring()
+}
+
+// GetPlugboard returns the connected plugboard pairs (empty if there is no plugboard or no plugs are connected)
+func (e *Enigma) GetPlugboard() string {
+	return e.plugboard.getPairs()
+}
+
 // -------------------------------------- SETUP --------------------------------------
 
 // RotorsSetup fully configures all rotors in this Enigma machine
@@ -146,7 +286,7 @@ func (e *Enigma) getRotors(rotorModels map[RotorSlot]RotorModel) ([]rotor, error
 		}
 
 		// all good, add the rotor
-		rotors[slot] = newRotor(rotorModel)
+		rotors[slot] = newRotor(rotorModel, e.alphabet)
 		isDuplicateModel[rotorModel] = struct{}{}
 	}
 
@@ -169,12 +309,26 @@ func (e *Enigma) RotorSetRing(slot RotorSlot, position int) error {
 	return e.rotors[e.rotorSlotToIndex(slot)].setRingPosition(position)
 }
 
+// SetStepping replaces the stepping mechanism of the model, ie. to experiment with different machine designs
+// (nil restores the mechanism of the model). The stepping mechanism is not part of the Settings
+func (e *Enigma) SetStepping(stepping Stepping) error {
+	if stepping == nil {
+		stepping = e.Model.GetStepping()
+	}
+	if err := validateStepping(stepping, len(e.rotors)); err != nil {
+		return err
+	}
+	e.stepping = stepping
+	return nil
+}
+
 // RotorsReset resets the rotors to their starting (wheel) positions.
 // This is necessary before encoding / decoding another message as the rotors move after every encoded letter
 func (e *Enigma) RotorsReset() {
 	for slot := range e.rotors {
 		e.rotors[slot].reset()
 	}
+	e.reflector.reset()
 }
 
 // ReflectorSetup fully configures the reflector in this Enigma machine
@@ -218,7 +372,7 @@ func (e *Enigma) getReflector(reflectorModel ReflectorModel) (reflector, error)
 		return reflector{}, fmt.Errorf("%s model does not support reflector %s", e.GetName(), reflectorModel)
 	}
 
-	return newReflector(reflectorModel), nil
+	return newReflector(reflectorModel, e.alphabet), nil
 }
 
 // ReflectorSetWheel sets the reflector in this Enigma machine to the given position (only for movable reflectors)
@@ -257,46 +411,88 @@ func (e *Enigma) rotorIndexToSlot(index int) RotorSlot {
 
 // -------------------------------------- ENCODING --------------------------------------
 
+// EncodeOption modifies the behaviour of Encode
+type EncodeOption func(options *encodeOptions)
+
+type encodeOptions struct {
+	passThrough bool
+}
+
+// PassThrough keeps the characters not supported by Enigma (spaces, digits, punctuation, ...) in place without encoding them
+// and without stepping the rotors, lowercase letters are encoded and stay lowercase. The output has the same layout as the input
+func PassThrough() EncodeOption {
+	return func(options *encodeOptions) {
+		options.passThrough = true
+	}
+}
+
 // Encode encodes the given test (for decoding reset the reflectors and run with the encoded text)
-func (e *Enigma) Encode(text string) (string, error) {
-	result, _, err := e.doEncode(text)
+func (e *Enigma) Encode(text string, options ...EncodeOption) (string, error) {
+	config := encodeOptions{}
+	for _, option := range options {
+		option(&config)
+	}
+	result, _, err := e.doEncode(text, false, config)
 	return result, err
 }
 
 // EncodeVerbose used for debugging the encoding process,
 // returns detailed encryption sequences instead of just the encrypted text
 func (e *Enigma) EncodeVerbose(text string) ([]EncryptionSequence, error) {
-	_, sequences, err := e.doEncode(text)
+	_, sequences, err := e.doEncode(text, true, encodeOptions{})
 	return sequences, err
 }
 
-func (e *Enigma) doEncode(text string) (string, []EncryptionSequence, error) {
-	result := make([]byte, len(text))
-	sequences := make([]EncryptionSequence, len(text))
-	for i, letter := range text {
-		sequence, err := e.translate(byte(letter))
+func (e *Enigma) doEncode(text string, verbose bool, options encodeOptions) (string, []EncryptionSequence, error) {
+	var result strings.Builder
+	result.Grow(len(text))
+	var sequences []EncryptionSequence
+	for _, letter := range text {
+		in, lower := letter, false
+		if options.passThrough {
+			if upper := unicode.ToUpper(letter); upper != letter && e.alphabet.contains(upper) {
+				in, lower = upper, true
+			}
+			if !e.alphabet.contains(in) {
+				result.WriteRune(letter) // kept as it is, rotors do not move
+				continue
+			}
+		}
+		if !e.alphabet.contains(in) {
+			return "", nil, fmt.Errorf("failed to encode letter \"%s\": unsupported letter", string(letter))
+		}
+
+		var sequence *EncryptionSequence // only record the sequence when needed, it is expensive
+		if verbose {
+			sequences = append(sequences, EncryptionSequence{})
+			sequence = &sequences[len(sequences)-1]
+		}
+		encoded, err := e.translate(byte(in), sequence)
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to encode letter \"%s\": %w", string(letter), err)
 		}
-		result[i] = sequence.GetResult()
-		sequences[i] = sequence
+		if lower {
+			result.WriteRune(unicode.ToLower(rune(e.alphabet.intToChar(encoded))))
+		} else {
+			result.WriteByte(e.alphabet.intToChar(encoded))
+		}
 	}
-	return string(result), sequences, nil
+	return result.String(), sequences, nil
 }
 
-func (e *Enigma) translate(in byte) (EncryptionSequence, error) {
-	letter, ok := Alphabet.charToInt(in)
+// translate encodes a single letter, the encryption steps are recorded to the sequence (if not nil)
+func (e *Enigma) translate(in byte, sequence *EncryptionSequence) (int, error) {
+	letter, ok := e.alphabet.charToInt(in)
 	if !ok {
-		return EncryptionSequence{}, fmt.Errorf("unsupported letter")
+		return 0, fmt.Errorf("unsupported letter")
 	}
 
 	// rotate the rotors first and start sequence
 	e.rotate()
-	sequence := EncryptionSequence{}
-	sequence.start(e.rotors, letter)
+	sequence.start(e.rotors, letter, e.alphabet)
 
 	// I. plugboard -> ETW
-	if e.HasPlugboard() {
+	if e.plugboard.isConfigurable {
 		letter = e.plugboard.translate(letter)
 		sequence.addStep("plugboard", letter)
 	}
@@ -305,12 +501,10 @@ func (e *Enigma) translate(in byte) (EncryptionSequence, error) {
 	letter = e.entryWheel.translateIn(letter)
 	sequence.addStep("etw", letter)
 
-	// III. rotors -> reflector (reverse order of rotors, the letter goes from right to left)
-	slots := e.GetAvailableRotorSlots()
-	for _, slot := range slots {
-		slotIndex := e.rotorSlotToIndex(slot)
+	// III. rotors -> reflector (rotors are ordered right to left, the same way the letter goes)
+	for slotIndex := range e.rotors {
 		letter = e.rotors[slotIndex].translateIn(letter)
-		sequence.addStep(fmt.Sprintf("rotor %d", slotIndex+1), letter)
+		sequence.addRotorStep(slotIndex, letter)
 	}
 
 	// IV. reflector -> rotors
@@ -318,10 +512,9 @@ func (e *Enigma) translate(in byte) (EncryptionSequence, error) {
 	sequence.addStep("reflector", letter)
 
 	// V. rotors -> ETW
-	for i := len(slots) - 1; i >= 0; i-- {
-		slotIndex := e.rotorSlotToIndex(slots[i])
+	for slotIndex := len(e.rotors) - 1; slotIndex >= 0; slotIndex-- {
 		letter = e.rotors[slotIndex].translateOut(letter)
-		sequence.addStep(fmt.Sprintf("rotor %d", slotIndex+1), letter)
+		sequence.addRotorStep(slotIndex, letter)
 	}
 
 	// VI. ETW -> plugboard
@@ -329,27 +522,15 @@ func (e *Enigma) translate(in byte) (EncryptionSequence, error) {
 	sequence.addStep("etw", letter)
 
 	// VII. plugboard -> output bulb
-	if e.HasPlugboard() {
+	if e.plugboard.isConfigurable {
 		letter = e.plugboard.translate(letter)
 		sequence.addStep("plugboard", letter)
 	}
 
 	sequence.finish(letter)
-	return sequence, nil
+	return letter, nil
 }
 
 func (e *Enigma) rotate() {
-	// determine which rotors should be rotated in this step
-	rotateMiddle := e.rotors[e.rotorSlotToIndex(Right)].shouldRotateNext()
-	rotateLeft := e.rotors[e.rotorSlotToIndex(Middle)].shouldRotateNext()
-
-	e.rotors[e.rotorSlotToIndex(Right)].rotate() // always rotate the right rotor
-	if rotateMiddle {
-		e.rotors[e.rotorSlotToIndex(Middle)].rotate()
-	}
-	if rotateLeft {
-		// double-stepping - middle rotor rotates again if left rotor rotates
-		e.rotors[e.rotorSlotToIndex(Middle)].rotate()
-		e.rotors[e.rotorSlotToIndex(Left)].rotate()
-	}
+	e.stepping.Step(machineWheels{e: e})
 }
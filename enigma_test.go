@@ -3,7 +3,9 @@ package enigma
 import (
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"unicode"
 )
 
 type enigmaSpec struct {
@@ -93,6 +95,29 @@ func TestEnigma_Encode(t *testing.T) {
 			text: "THEQQENIGMAQQTQQTIRPITZQQWASQQAQQSPECIALQQVERSIONQQOFQQTHEQQENIGMAQQKQQTHATQQWASQQMADEQQFORQQTHEQQJAPANESEQQARMYQQDURINGQQWWIIQQTHEQQWHEELSQQWEREQQWIREDQQDIFFERENTLYQQANDQQEACHQQHADQQFIVEQQTURNOVERQQNOTCHESQQQQTHEQQTABLEQQBELOWQQSHOWSQQTHEQQWIRINGQQOFQQTHEQQWHEELSQQTHEQQETWQQANDQQUKW",
 			want: "NSLLDBIGRLEJHUKZRVIOYXAPGYDZLIKWILEVAGJKXBJBQTMTKSHSHXPVCJYUWJFLPHSJQIGEUBIKHPBONFFBHYTSIHJCUDFOPNEYTVLBVCWIGXADLLZRFGHCNCYHMPYGFJONRBXMAQANGKXOLZLTXMVWHNZLQDNJQDLXGATRRNGOIHNQMKVYPJFUSAPIAQDHVJUATOXYFSNTVWEHIYXEXZJMGICNRLDKKNEAWGRHKDRNBCLSTJFXNZYBCEGBWCSRLCIRAOHYNHEDCEIZILFMTAPMGEFD",
 		},
+		{
+			// regression vector generated by this library, not a published one
+			name: "Railway",
+			spec: enigmaSpec{
+				model:           Railway,
+				rotorConfig:     "III-R I-R II-R | K F Q | 4 12 20",
+				reflectorConfig: " | M | ",
+			},
+			text: "THEQQREICHSBAHNQQUSEDQQAQQMODIFIEDQQCOMMERCIALQQENIGMAQQKQQWITHQQREWIREDQQWHEELSQQANDQQREFLECTOR",
+			want: "GTBITSSHMBRFYWBEEKYNPEUOWWZLEKMCKRURJGZLHOLVQFSPDVKDXGEEBUUGZJNFPPGDXDBTMYMIXRRIFEDQFFNQSHFLSJGT",
+		},
+		{
+			// regression vector generated by this library, not a published one
+			name: "Norway",
+			spec: enigmaSpec{
+				model:           Norway,
+				rotorConfig:     "IV-N II-N V-N | N O R | 8 3 15",
+				reflectorConfig: "N | | ",
+				plugboardConfig: "AN BW CE DX FK GT",
+			},
+			text: "AFTERQQTHEQQWARQQTHEQQNORWEGIANQQPOLICEQQUSEDQQCAPTUREDQQENIGMAQQMACHINESQQWITHQQTHEIRQQOWNQQWIRING",
+			want: "QCOJFLAJDLEVLJJLIDJRIHTBWMOFYLFZMYTWVZZPEDRNMPDUHSVWVJNADFLUJHUSUYHQMBTYHBXIBCWEDULPHKKMAPXTGKTLBXA",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -136,6 +161,14 @@ func TestEnigma_Decode(t *testing.T) {
 				plugboardConfig: "ZY WV JF ES LO",
 			},
 		},
+		{
+			name: "G-312",
+			spec: enigmaSpec{
+				model:           G312,
+				rotorConfig:     "II-G312 III-G312 I-G312 | M A Q | 3 21 9",
+				reflectorConfig: " | R | ",
+			},
+		},
 	}
 	texts := []string{
 		"Simple text with punctuation, nothing special.",
@@ -179,6 +212,133 @@ func TestEnigma_Decode(t *testing.T) {
 	}
 }
 
+func TestEnigma_Stepping(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      enigmaSpec
+		stepping  Stepping // model default if empty
+		presses   int
+		want      string // reflector, left, middle and right wheel positions
+		wantReset string
+	}{
+		{
+			name:      "lever double step",
+			spec:      enigmaSpec{model: M3, rotorConfig: "I II III | A D U | ", reflectorConfig: "B | | "},
+			presses:   3,
+			want:      "ABFX",
+			wantReset: "AADU",
+		},
+		{
+			name:      "lever with both turnovers at once",
+			spec:      enigmaSpec{model: M3, rotorConfig: "I II III | A E V | ", reflectorConfig: "B | | "},
+			presses:   1,
+			want:      "ABFW", // middle rotor pushed by two pawls still moves just once
+			wantReset: "AAEV",
+		},
+		{
+			name:      "railway turnovers",
+			spec:      enigmaSpec{model: Railway, rotorConfig: "II-R III-R I-R | A X N | ", reflectorConfig: " | A | "},
+			presses:   2,
+			want:      "ABZP", // rotor I-R turns over at N, rotor III-R at Y (the other way round than on the Enigma K)
+			wantReset: "AAXN",
+		},
+		{
+			name:      "gears without double step",
+			spec:      enigmaSpec{model: G31, rotorConfig: "III-G31 II-G31 I-G31 | A E Q | ", reflectorConfig: " | A | "},
+			presses:   2,
+			want:      "AAFS",
+			wantReset: "AAEQ",
+		},
+		{
+			name:      "gears stepping the reflector",
+			spec:      enigmaSpec{model: G260, rotorConfig: "III-G260 II-G260 I-G260 | A C A | ", reflectorConfig: " | Z | "},
+			presses:   1,
+			want:      "ABDB",
+			wantReset: "ZACA",
+		},
+		{
+			name:      "typex with a stator",
+			spec:      enigmaSpec{model: M4, rotorConfig: "beta I II III | A A D U | ", reflectorConfig: "BThin | | "},
+			stepping:  mustTypexStepping(1),
+			presses:   3,
+			want:      "ABGU",
+			wantReset: "AADU",
+		},
+		{
+			name: "custom",
+			spec: enigmaSpec{model: M3, rotorConfig: "I II III | A D U | ", reflectorConfig: "B | | "},
+			stepping: SteppingFunc(func(wheels Wheels) {
+				if wheels.GetWheel(Right) == 'U' {
+					wheels.Rotate(Left) // only ever rotates the left rotor
+				}
+			}),
+			presses:   2,
+			want:      "ACDU",
+			wantReset: "AADU",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := createEnigma(tt.spec.model, tt.spec.rotorConfig, tt.spec.reflectorConfig, tt.spec.plugboardConfig)
+			if err != nil {
+				t.Errorf("config error = %v", err)
+				return
+			}
+			if err = e.SetStepping(tt.stepping); err != nil {
+				t.Errorf("stepping error = %v", err)
+				return
+			}
+			if _, err = e.Encode(strings.Repeat("A", tt.presses)); err != nil {
+				t.Errorf("encode error = %v", err)
+				return
+			}
+			if got := getPositions(&e); got != tt.want {
+				t.Errorf("want = %v\n got = %v", tt.want, got)
+			}
+			e.RotorsReset()
+			if got := getPositions(&e); got != tt.wantReset {
+				t.Errorf("reset want = %v\n got = %v", tt.wantReset, got)
+			}
+		})
+	}
+}
+
+func TestEnigma_SetSteppingError(t *testing.T) {
+	for _, stators := range []int{-1, 4} {
+		if _, err := TypexStepping(stators); err == nil {
+			t.Errorf("expected error for %d stators, got none", stators)
+		}
+	}
+	e, _ := NewEnigma(M3)
+	if err := e.SetStepping(mustTypexStepping(3)); err == nil {
+		t.Errorf("expected error for stators of all the rotors, got none")
+	}
+	if err := e.SetStepping(mustTypexStepping(2)); err != nil {
+		t.Errorf("stepping error = %v", err)
+	}
+	err := RegisterModel("Err", ModelDefinition{Reflectors: []ReflectorModel{UkwB}, Rotors: []RotorModel{RotorI, RotorII, RotorIII}, Stepping: mustTypexStepping(3)})
+	if err == nil {
+		t.Errorf("expected model stepping error, got none")
+	}
+}
+
+func mustTypexStepping(stators int) Stepping {
+	stepping, err := TypexStepping(stators)
+	if err != nil {
+		panic(err)
+	}
+	return stepping
+}
+
+func getPositions(e *Enigma) string {
+	positions := []byte{e.GetReflectorWheel()}
+	for _, slot := range []RotorSlot{Left, Middle, Right} {
+		wheel, _ := e.GetRotorWheel(slot)
+		positions = append(positions, wheel)
+	}
+	return string(positions)
+}
+
 func TestEnigma_ConfigurationError(t *testing.T) {
 	tests := []struct {
 		name string
@@ -293,6 +453,10 @@ func TestEnigma_EncodeAlphabet(t *testing.T) {
 			name: "unsupported symbol",
 			text: "PUNCTUATION.ISQQNOTQQSUPPORTED",
 		},
+		{
+			name: "multibyte letter",
+			text: "ŁA", // must not be cut to bytes
+		},
 		{
 			name: "unsupported symbol with preprocess",
 			text: Preprocess("Only some basic punctuation symbols are supported by Preprocess()."),
@@ -314,6 +478,250 @@ func TestEnigma_EncodeAlphabet(t *testing.T) {
 	}
 }
 
+func TestEnigma_EncodePassThrough(t *testing.T) {
+	text := "Hello, World! 1940 – Łódź\n\tQUIT"
+	e, err := createEnigma(M3, "I II III | B C D | 3 4 5", "C | |", "AB CD EF")
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	encoded, err := e.Encode(text, PassThrough())
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+
+	// same layout, only the letters encoded
+	want, got := []rune(text), []rune(encoded)
+	if len(got) != len(want) {
+		t.Errorf("want = %v runes\n got = %v runes", len(want), len(got))
+		return
+	}
+	var letters []rune
+	for i := range want {
+		isLetter := (want[i] >= 'A' && want[i] <= 'Z') || (want[i] >= 'a' && want[i] <= 'z')
+		if !isLetter && got[i] != want[i] {
+			t.Errorf("character %q at position %d changed to %q", want[i], i, got[i])
+		}
+		if isLetter {
+			if unicode.IsLower(want[i]) != unicode.IsLower(got[i]) {
+				t.Errorf("letter case at position %d changed", i)
+			}
+			letters = append(letters, unicode.ToUpper(got[i]))
+		}
+	}
+
+	// skipped characters do not step the rotors
+	e.RotorsReset()
+	lettersOnly, _ := e.Encode("HELLOWORLDDQUIT")
+	if string(letters) != lettersOnly {
+		t.Errorf("want = %v\n got = %v", lettersOnly, string(letters))
+	}
+
+	e.RotorsReset()
+	if decoded, _ := e.Encode(encoded, PassThrough()); decoded != text {
+		t.Errorf("want = %v\n got = %v", text, decoded)
+	}
+}
+
+func TestEnigma_Settings(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     enigmaSpec
+		settings string
+	}{
+		{
+			name:     "M3 with plugboard",
+			spec:     enigmaSpec{M3, "III VII VIII | D U S | 12 8 6", "C | |", "OP AI BX CU"},
+			settings: "M3 C III-VII-VIII 12-08-06 DUS AI BX CU OP",
+		},
+		{
+			name:     "default UKW-D wiring",
+			spec:     enigmaSpec{M4UKWD, "", "", ""},
+			settings: "M4-UKW-D D=AVBOCTDMEZFNGXHQISKRLUPW III-II-I 01-01-01 AAA",
+		},
+		{
+			name:     "rewired UKW-D",
+			spec:     enigmaSpec{M4UKWD, "II VIII I | Q N G | 11 11 9", "D | | IQ HG CL DA NK FX BZ MW EV OR PU TS", "ZY WV"},
+			settings: "M4-UKW-D D=ADBZCLEVFXGHIQKNMWORPUST II-VIII-I 11-11-09 QNG VW YZ",
+		},
+		{
+			name:     "movable reflector",
+			spec:     enigmaSpec{SwissK, "II-SK I-SK III-SK | A X L | 2 19 4", " | F | ", ""},
+			settings: "Swiss-K K@F II-SK-I-SK-III-SK 02-19-04 AXL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := createEnigma(tt.spec.model, tt.spec.rotorConfig, tt.spec.reflectorConfig, tt.spec.plugboardConfig)
+			if err != nil {
+				t.Errorf("config error = %v", err)
+				return
+			}
+			if _, err = e.Encode("ENCODINGMOVESTHEROTORS"); err != nil {
+				t.Errorf("encode error = %v", err)
+				return
+			}
+
+			if got := e.Settings().String(); got != tt.settings {
+				t.Errorf("want = %v\n got = %v", tt.settings, got)
+			}
+
+			// the snapshot must recreate identical machine
+			clone, err := NewEnigmaFromSettings(e.Settings())
+			if err != nil {
+				t.Errorf("config error = %v", err)
+				return
+			}
+			if got := clone.Settings().String(); got != tt.settings {
+				t.Errorf("recreated machine differs\nwant = %v\n got = %v", tt.settings, got)
+			}
+		})
+	}
+}
+
+func TestEnigma_Getters(t *testing.T) {
+	e, err := createEnigma(M4, "beta II IV I | A B C D | 1 2 3 4", "CThin | |", "AT BG")
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	if _, err = e.Encode("AAA"); err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+
+	model, err := e.GetRotorModel(Middle)
+	if err != nil || model != RotorIV {
+		t.Errorf("rotor model want = %v, got = %v (error %v)", RotorIV, model, err)
+	}
+	ring, err := e.GetRotorRing(Fourth)
+	if err != nil || ring != 1 {
+		t.Errorf("ring position want = %v, got = %v (error %v)", 1, ring, err)
+	}
+	wheel, err := e.GetRotorWheel(Right)
+	if err != nil || wheel != 'G' {
+		t.Errorf("wheel position want = %v, got = %v (error %v)", "G", string(wheel), err)
+	}
+	initialWheel, err := e.GetRotorInitialWheel(Right)
+	if err != nil || initialWheel != 'D' {
+		t.Errorf("initial wheel position want = %v, got = %v (error %v)", "D", string(initialWheel), err)
+	}
+	if got := e.GetReflectorWheel(); got != 'A' {
+		t.Errorf("reflector wheel position want = %v, got = %v", "A", string(got))
+	}
+	if got := e.GetReflectorWiring(); got != "" {
+		t.Errorf("reflector wiring want = %v, got = %v", "", got)
+	}
+	if got := e.GetPlugboard(); got != "AT BG" {
+		t.Errorf("plugboard want = %v, got = %v", "AT BG", got)
+	}
+
+	m3, _ := NewEnigma(M3)
+	if _, err = m3.GetRotorModel(Fourth); err == nil {
+		t.Errorf("expected unsupported slot error, got none")
+	}
+}
+
+func TestEnigma_StateRestore(t *testing.T) {
+	e, err := createEnigma(M4, "gamma VI I VII | L X A Q | 18 16 23 2", "BThin | |", "AB CD")
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	text := strings.Repeat("ENIGMA", 100)
+	want, err := e.Encode(text)
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+
+	// encode in chunks, backtracking on every chunk
+	e.RotorsReset()
+	got := ""
+	for i := 0; i < len(text); i += 50 {
+		state := e.State()
+		if _, err = e.Encode("XXXXXXXXXX"); err != nil {
+			t.Errorf("encode error = %v", err)
+			return
+		}
+		if err = e.Restore(state); err != nil {
+			t.Errorf("restore error = %v", err)
+			return
+		}
+		chunk, err := e.Encode(text[i : i+50])
+		if err != nil {
+			t.Errorf("encode error = %v", err)
+			return
+		}
+		got += chunk
+	}
+	if got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+
+	// restore must not change the starting positions
+	e.RotorsReset()
+	if wheel, _ := e.GetRotorWheel(Right); wheel != 'Q' {
+		t.Errorf("reset after restore want wheel = %v, got = %v", "Q", string(wheel))
+	}
+
+	m3, _ := NewEnigma(M3)
+	if err = m3.Restore(e.State()); err == nil {
+		t.Errorf("expected restore error for different model, got none")
+	}
+}
+
+func TestEnigma_Clone(t *testing.T) {
+	template, err := createEnigma(M3, "VI I V | B T H | 12 1 5", "C | |", "AB CD EF")
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	text := strings.Repeat("CLONE", 50)
+	want, err := template.Encode(text)
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+	template.RotorsReset()
+
+	// clones used concurrently must not step each other's rotors
+	results := make([]string, 8)
+	errs := make([]error, len(results))
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int, e Enigma) {
+			defer wg.Done()
+			results[i], errs[i] = e.Encode(text)
+		}(i, template.Clone())
+	}
+	wg.Wait()
+	for i, got := range results {
+		if errs[i] != nil {
+			t.Errorf("encode error = %v", errs[i])
+			continue
+		}
+		if got != want {
+			t.Errorf("clone %d\nwant = %v\n got = %v", i, want, got)
+		}
+	}
+
+	// reconfiguring the clone must not affect the template
+	clone := template.Clone()
+	if err = clone.PlugboardSetup("XY"); err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	if got := template.GetPlugboard(); got != "AB CD EF" {
+		t.Errorf("template plugboard want = %v, got = %v", "AB CD EF", got)
+	}
+	if wheel, _ := template.GetRotorWheel(Right); wheel != 'H' {
+		t.Errorf("template wheel position want = %v, got = %v", "H", string(wheel))
+	}
+}
+
 func createEnigma(model Model, rotorConfigString, reflectorConfigString, plugboardConfig string) (Enigma, error) {
 	// Rotors
 	rotorsConfig := make(map[RotorSlot]RotorConfig)
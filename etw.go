@@ -9,17 +9,17 @@ type etw struct {
 	letterMapOut map[int]int
 }
 
-func newEtw(wiring etwWiring) etw {
+func newEtw(wiring etwWiring, a *alphabet) etw {
 	letterMapIn := map[int]int{}
 	letterMapOut := map[int]int{}
 	isDuplicate := map[int]struct{}{}
-	for i := 0; i < Alphabet.getSize(); i++ {
+	for i := 0; i < a.getSize(); i++ {
 		if i > len(wiring) {
 			panic(fmt.Errorf("invalid ETW wiring, does not cover the whole alphabet"))
 		}
-		mappedIndex, ok := Alphabet.charToInt(wiring[i])
+		mappedIndex, ok := a.charToInt(wiring[i])
 		if !ok {
-			panic(fmt.Errorf("invalid ETW wiring pair %s->%s", string(Alphabet.intToChar(i)), string(wiring[i])))
+			panic(fmt.Errorf("invalid ETW wiring pair %s->%s", string(a.intToChar(i)), string(wiring[i])))
 		}
 		if _, ok := isDuplicate[mappedIndex]; ok {
 			panic(fmt.Errorf("invalid ETW wiring, letter %s is duplicate", string(wiring[i])))
@@ -35,6 +35,13 @@ func newEtw(wiring etwWiring) etw {
 	}
 }
 
+func (e *etw) clone() etw {
+	return etw{
+		letterMapIn:  copyLetterMap(e.letterMapIn),
+		letterMapOut: copyLetterMap(e.letterMapOut),
+	}
+}
+
 func (e *etw) translateIn(letter int) int {
 	return e.letterMapIn[letter]
 }
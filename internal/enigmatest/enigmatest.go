@@ -0,0 +1,43 @@
+// Package enigmatest provides the shared fixtures for the tests of the enigma packages
+package enigmatest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+// Numeric is a toy model with the keyboard of ten digits "0123456789", available after calling RegisterNumeric
+const Numeric enigma.Model = "Numeric"
+
+// NumericSettings is a valid key-sheet shorthand of the Numeric model
+const NumericSettings = "Numeric Numeric III-Numeric-I-Numeric-II-Numeric 02-10-07 381"
+
+var numericOnce sync.Once
+
+// RegisterNumeric registers the Numeric model (only once, can be called from every test)
+func RegisterNumeric() enigma.Model {
+	numericOnce.Do(func() {
+		const digits = "0123456789"
+		rotors := map[enigma.RotorModel]string{"I-Numeric": "7019384265", "II-Numeric": "2946018573", "III-Numeric": "5203876194"}
+		for model, wiring := range rotors {
+			if err := enigma.RegisterRotor(model, enigma.RotorDefinition{Wiring: wiring, NotchPositions: "9", Alphabet: digits}); err != nil {
+				panic(fmt.Errorf("failed to register rotor: %w", err))
+			}
+		}
+		if err := enigma.RegisterReflector("Numeric", enigma.ReflectorDefinition{Wiring: "5879604213", Alphabet: digits}); err != nil {
+			panic(fmt.Errorf("failed to register reflector: %w", err))
+		}
+		err := enigma.RegisterModel(Numeric, enigma.ModelDefinition{
+			HasPlugboard: true,
+			Reflectors:   []enigma.ReflectorModel{"Numeric"},
+			Rotors:       []enigma.RotorModel{"I-Numeric", "II-Numeric", "III-Numeric"},
+			Alphabet:     digits,
+		})
+		if err != nil {
+			panic(fmt.Errorf("failed to register model: %w", err))
+		}
+	})
+	return Numeric
+}
@@ -0,0 +1,58 @@
+// Package toolkit contains the helpers shared by the cryptanalysis and the operating procedure packages
+package toolkit
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+// GetSlots returns the rotor slots of the model from left to right (the order of the letters in the keys and indicators)
+func GetSlots(model enigma.Model) []enigma.RotorSlot {
+	available := model.GetAvailableRotorSlots()
+	slots := make([]enigma.RotorSlot, len(available))
+	for i, slot := range available {
+		slots[len(available)-1-i] = slot
+	}
+	return slots
+}
+
+// SetWheels sets the wheel positions of the given rotor slots, one letter of the positions per slot
+func SetWheels(e *enigma.Enigma, slots []enigma.RotorSlot, positions string) error {
+	for i, slot := range slots {
+		if err := e.RotorSetWheel(slot, positions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RandomLetters returns n random letters A-Z
+func RandomLetters(random *rand.Rand, n int) string {
+	letters := make([]byte, n)
+	for i := range letters {
+		letters[i] = byte('A' + random.Intn(enigma.AlphabetSize))
+	}
+	return string(letters)
+}
+
+// RunParallel calls the given function for all the indexes in [0, count) using the given number of workers
+func RunParallel(workers, count int, run func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				run(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
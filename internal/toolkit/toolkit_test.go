@@ -0,0 +1,49 @@
+package toolkit
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+func TestGetSlots(t *testing.T) {
+	if got, want := GetSlots(enigma.M4), []enigma.RotorSlot{enigma.Fourth, enigma.Left, enigma.Middle, enigma.Right}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
+
+func TestSetWheels(t *testing.T) {
+	e, _ := enigma.NewEnigma(enigma.M3)
+	slots := GetSlots(enigma.M3)
+	if err := SetWheels(&e, slots, "QEV"); err != nil {
+		t.Errorf("wheel error = %v", err)
+		return
+	}
+	if wheel, _ := e.GetRotorWheel(enigma.Left); wheel != 'Q' {
+		t.Errorf("want = %v\n got = %v", "Q", string(wheel))
+	}
+	if err := SetWheels(&e, slots, "QE1"); err == nil {
+		t.Errorf("expected wheel error, got none")
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	var sum int64
+	RunParallel(4, 100, func(i int) {
+		atomic.AddInt64(&sum, int64(i))
+	})
+	if sum != 4950 {
+		t.Errorf("want = %v\n got = %v", 4950, sum)
+	}
+}
+
+func TestRandomLetters(t *testing.T) {
+	letters := RandomLetters(rand.New(rand.NewSource(1)), 100)
+	if len(letters) != 100 || strings.IndexFunc(letters, func(r rune) bool { return r < 'A' || r > 'Z' }) != -1 {
+		t.Errorf("invalid random letters %s", letters)
+	}
+}
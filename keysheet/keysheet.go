@@ -0,0 +1,217 @@
+// Package keysheet generates the monthly key sheets (Schlüsselliste) following the historic rules:
+//   - no rotor order (Walzenlage) repeats within the month
+//   - 10 plugboard pairs, no pair connects letters adjacent in the alphabet
+//   - naval sheets use at least one of the naval rotors VI, VII and VIII every day
+package keysheet
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+)
+
+const (
+	alphabetSize     = enigma.AlphabetSize
+	plugboardPairs   = 10
+	kenngruppenCount = 4
+)
+
+var navalRotors = map[enigma.RotorModel]bool{enigma.RotorVI: true, enigma.RotorVII: true, enigma.RotorVIII: true}
+
+// Config specifies the key sheet to generate
+type Config struct {
+	Model     enigma.Model
+	Reflector enigma.ReflectorModel // default reflector of the model if empty
+	Naval     bool                  // naval sheet (always for the M4 models)
+	Year      int
+	Month     time.Month
+}
+
+// Day contains the settings of a single day of the key sheet
+type Day struct {
+	Day         int
+	Settings    enigma.Settings // rotor order, ring settings, plugboard and the ground setting (wheel positions)
+	Kenngruppen []string        // indicator groups identifying the key net
+}
+
+// Sheet is the key sheet for the whole month
+type Sheet struct {
+	Model enigma.Model
+	Naval bool
+	Year  int
+	Month time.Month
+	Days  []Day
+}
+
+// Generate generates the key sheet for every day of the month
+func Generate(config Config, random *rand.Rand) (Sheet, error) {
+	if config.Model == enigma.M4 || config.Model == enigma.M4UKWD {
+		config.Naval = true
+	}
+	if config.Month < time.January || config.Month > time.December {
+		return Sheet{}, fmt.Errorf("invalid month %d", config.Month)
+	}
+	if _, err := enigma.NewEnigma(config.Model); err != nil {
+		return Sheet{}, err
+	}
+	if err := config.Model.RequireBasicAlphabet(); err != nil {
+		return Sheet{}, err
+	}
+
+	var orders []map[enigma.RotorSlot]enigma.RotorModel
+	for _, order := range config.Model.GetRotorOrders() {
+		if !config.Naval || hasNavalRotor(order) {
+			orders = append(orders, order)
+		}
+	}
+	days := time.Date(config.Year, config.Month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if len(orders) < days {
+		return Sheet{}, fmt.Errorf("%s model only has %d valid rotor orders, %d needed for the month", config.Model.GetName(), len(orders), days)
+	}
+
+	sheet := Sheet{Model: config.Model, Naval: config.Naval, Year: config.Year, Month: config.Month, Days: make([]Day, days)}
+	slots := config.Model.GetAvailableRotorSlots()
+	for i, orderIndex := range random.Perm(len(orders))[:days] {
+		settings := enigma.Settings{
+			Model:     config.Model,
+			Rotors:    make(map[enigma.RotorSlot]enigma.RotorConfig, len(slots)),
+			Reflector: enigma.ReflectorConfig{Model: config.Reflector},
+		}
+		for _, slot := range slots {
+			settings.Rotors[slot] = enigma.RotorConfig{
+				Model:         orders[orderIndex][slot],
+				WheelPosition: byte('A' + random.Intn(alphabetSize)),
+				RingPosition:  1 + random.Intn(alphabetSize),
+			}
+		}
+		if config.Model.HasPlugboard() {
+			settings.Plugboard = randomPlugboard(random)
+		}
+		e, err := enigma.NewEnigmaFromSettings(settings)
+		if err != nil {
+			return Sheet{}, fmt.Errorf("invalid settings generated for day %d: %w", i+1, err)
+		}
+		if reflector := e.Settings().Reflector; reflector.Model.IsRewirable() {
+			// rewirable reflector wiring is a part of the daily key (UKW-D)
+			reflector.Wiring = randomReflectorWiring(random)
+			if err = e.ReflectorRewire(reflector.Wiring); err != nil {
+				return Sheet{}, fmt.Errorf("invalid reflector wiring generated for day %d: %w", i+1, err)
+			}
+			settings.Reflector = reflector
+		}
+
+		kenngruppen := make([]string, kenngruppenCount)
+		for j := range kenngruppen {
+			kenngruppen[j] = toolkit.RandomLetters(random, 3)
+		}
+		sheet.Days[i] = Day{Day: i + 1, Settings: settings, Kenngruppen: kenngruppen}
+	}
+	return sheet, nil
+}
+
+func hasNavalRotor(order map[enigma.RotorSlot]enigma.RotorModel) bool {
+	for _, rotorModel := range order {
+		if navalRotors[rotorModel] {
+			return true
+		}
+	}
+	return false
+}
+
+// randomPlugboard returns 10 random letter pairs, none of them connecting letters adjacent in the alphabet
+func randomPlugboard(random *rand.Rand) string {
+	for {
+		letters := random.Perm(alphabetSize)[:2*plugboardPairs]
+		pairs := make([]string, 0, plugboardPairs)
+		for i := 0; i < len(letters); i += 2 {
+			a, b := letters[i], letters[i+1]
+			if a-b == 1 || b-a == 1 {
+				break
+			}
+			if a > b {
+				a, b = b, a
+			}
+			pairs = append(pairs, string([]byte{byte('A' + a), byte('A' + b)}))
+		}
+		if len(pairs) == plugboardPairs {
+			sort.Strings(pairs)
+			return strings.Join(pairs, " ")
+		}
+	}
+}
+
+// randomReflectorWiring returns 12 random letter pairs of the UKW-D reflector, the letters J and Y (B and O in the Bletchley
+// notation) are always connected, so they are never a part of the configurable wiring
+func randomReflectorWiring(random *rand.Rand) string {
+	letters := make([]byte, 0, alphabetSize-2)
+	for c := byte('A'); c <= 'Z'; c++ {
+		if c != 'J' && c != 'Y' {
+			letters = append(letters, c)
+		}
+	}
+	random.Shuffle(len(letters), func(i, j int) { letters[i], letters[j] = letters[j], letters[i] })
+	pairs := make([]string, 0, len(letters)/2)
+	for i := 0; i < len(letters); i += 2 {
+		a, b := letters[i], letters[i+1]
+		if a > b {
+			a, b = b, a
+		}
+		pairs = append(pairs, string([]byte{a, b}))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// Settings returns the machine settings of the given day of the month
+func (s Sheet) Settings(day int) (enigma.Settings, error) {
+	if day < 1 || day > len(s.Days) {
+		return enigma.Settings{}, fmt.Errorf("invalid day %d, the sheet has %d days", day, len(s.Days))
+	}
+	return s.Days[day-1].Settings, nil
+}
+
+// String prints the sheet the historic way - last day of the month first, so that the used days could be cut off and destroyed.
+// The reflector wiring (Umkehrwalze) column is only printed for the sheets with a rewirable reflector
+func (s Sheet) String() string {
+	var b strings.Builder
+	kind := "Heeres"
+	if s.Naval {
+		kind = "Marine"
+	}
+	withWiring := false
+	for _, day := range s.Days {
+		withWiring = withWiring || day.Settings.Reflector.Wiring != ""
+	}
+	slots := s.Model.GetAvailableRotorSlots()
+
+	fmt.Fprintf(&b, "Geheim! %s-Schlüssel %s für %02d/%d\n", kind, s.Model.GetName(), int(s.Month), s.Year)
+	fmt.Fprintf(&b, "%-5s | %-18s | ", "Datum", "Walzenlage")
+	if withWiring {
+		fmt.Fprintf(&b, "%-35s | ", "Umkehrwalze")
+	}
+	fmt.Fprintf(&b, "%-12s | %-29s | %-13s | %s\n", "Ringstellung", "Steckerverbindungen", "Grundstellung", "Kenngruppen")
+	for i := len(s.Days) - 1; i >= 0; i-- {
+		day := s.Days[i]
+		rotors := make([]string, 0, len(slots))
+		rings := make([]string, 0, len(slots))
+		wheels := make([]byte, 0, len(slots))
+		for j := len(slots) - 1; j >= 0; j-- { // left to right
+			config := day.Settings.Rotors[slots[j]]
+			rotors = append(rotors, string(config.Model))
+			rings = append(rings, fmt.Sprintf("%d", config.RingPosition))
+			wheels = append(wheels, config.WheelPosition)
+		}
+		fmt.Fprintf(&b, "%5d | %-18s | ", day.Day, strings.Join(rotors, " "))
+		if withWiring {
+			fmt.Fprintf(&b, "%-35s | ", day.Settings.Reflector.Wiring)
+		}
+		fmt.Fprintf(&b, "%-12s | %-29s | %-13s | %s\n",
+			strings.Join(rings, " "), day.Settings.Plugboard, string(wheels), strings.Join(day.Kenngruppen, " "))
+	}
+	return b.String()
+}
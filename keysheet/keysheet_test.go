@@ -0,0 +1,156 @@
+package keysheet
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name  string
+		model enigma.Model
+		naval bool
+		days  int
+	}{
+		{"army", enigma.One, false, 31},
+		{"navy M3", enigma.M3, true, 31},
+		{"navy M4", enigma.M4, false, 28},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			month := time.March
+			if tt.days == 28 {
+				month = time.February
+			}
+			sheet, err := Generate(Config{Model: tt.model, Naval: tt.naval, Year: 1943, Month: month}, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Errorf("generate error = %v", err)
+				return
+			}
+			if len(sheet.Days) != tt.days {
+				t.Errorf("want = %v\n got = %v", tt.days, len(sheet.Days))
+			}
+
+			orders := map[string]bool{}
+			for _, day := range sheet.Days {
+				if _, err = enigma.NewEnigmaWithSetup(day.Settings.Model, day.Settings.Rotors, day.Settings.Reflector, day.Settings.Plugboard); err != nil {
+					t.Errorf("invalid settings of day %d: %v", day.Day, err)
+				}
+				parts := strings.Fields(day.Settings.String())
+				if orders[parts[2]] {
+					t.Errorf("rotor order %s repeated", parts[2])
+				}
+				orders[parts[2]] = true
+				if sheet.Naval && !hasNavalRotor(getOrder(day.Settings)) {
+					t.Errorf("no naval rotor in %s", parts[2])
+				}
+
+				pairs := strings.Fields(day.Settings.Plugboard)
+				if len(pairs) != 10 {
+					t.Errorf("want = %v\n got = %v", 10, len(pairs))
+				}
+				for _, pair := range pairs {
+					if pair[1]-pair[0] == 1 {
+						t.Errorf("adjacent letters plugged %s", pair)
+					}
+				}
+				if len(day.Kenngruppen) != 4 {
+					t.Errorf("want = %v\n got = %v", 4, len(day.Kenngruppen))
+				}
+			}
+
+			printed := sheet.String()
+			if lines := strings.Split(strings.TrimSpace(printed), "\n"); len(lines) != tt.days+2 || !strings.HasPrefix(strings.TrimSpace(lines[2]), "28") && !strings.HasPrefix(strings.TrimSpace(lines[2]), "31") {
+				t.Errorf("invalid printed sheet:\n%s", printed)
+			}
+			if _, err = sheet.Settings(tt.days + 1); err == nil {
+				t.Errorf("expected invalid day error, got none")
+			}
+		})
+	}
+
+	if _, err := Generate(Config{Model: enigma.SwissK, Year: 1940, Month: time.May}, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("expected not enough rotor orders error, got none")
+	}
+	if _, err := Generate(Config{Model: enigmatest.RegisterNumeric(), Year: 1940, Month: time.May}, rand.New(rand.NewSource(1))); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+}
+
+func getOrder(settings enigma.Settings) map[enigma.RotorSlot]enigma.RotorModel {
+	order := map[enigma.RotorSlot]enigma.RotorModel{}
+	for slot, config := range settings.Rotors {
+		order[slot] = config.Model
+	}
+	return order
+}
+
+func TestSheet_String(t *testing.T) {
+	sheet := Sheet{Model: enigma.Tripitz, Naval: true, Year: 1943, Month: time.June, Days: []Day{{
+		Day: 1,
+		Settings: enigma.Settings{
+			Model: enigma.Tripitz,
+			Rotors: map[enigma.RotorSlot]enigma.RotorConfig{
+				enigma.Left:   {Model: enigma.RotorIIIT, WheelPosition: 'K', RingPosition: 4},
+				enigma.Middle: {Model: enigma.RotorIIT, WheelPosition: 'D', RingPosition: 17},
+				enigma.Right:  {Model: enigma.RotorVIT, WheelPosition: 'X', RingPosition: 26},
+			},
+		},
+		Kenngruppen: []string{"ABC", "DEF", "GHI", "JKL"},
+	}}}
+	lines := strings.Split(sheet.String(), "\n")
+	want := "    1 | III-T II-T VI-T    | 4 17 26      |                               | KDX           | ABC DEF GHI JKL"
+	if len(lines) != 4 || lines[2] != want {
+		t.Errorf("want = %v\n got = %v", want, lines)
+	}
+
+	// the daily UKW-D wiring is a part of the sheet
+	sheet, err := Generate(Config{Model: enigma.M4UKWD, Year: 1944, Month: time.February}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Errorf("generate error = %v", err)
+		return
+	}
+	wiring := sheet.Days[0].Settings.Reflector.Wiring
+	if wiring == "" {
+		t.Errorf("missing UKW-D wiring in the generated settings")
+	}
+	printed := sheet.String()
+	if !strings.Contains(printed, "Umkehrwalze") || !strings.Contains(printed, wiring) {
+		t.Errorf("UKW-D wiring %s not printed:\n%s", wiring, printed)
+	}
+}
+
+func TestGenerate_ReflectorWiring(t *testing.T) {
+	e, err := enigma.NewEnigma(enigma.M4UKWD)
+	if err != nil {
+		t.Errorf("enigma error = %v", err)
+		return
+	}
+	defaultWiring := e.Settings().Reflector.Wiring
+
+	wirings := map[string]bool{}
+	for _, seed := range []int64{1, 2} {
+		sheet, err := Generate(Config{Model: enigma.M4UKWD, Year: 1944, Month: time.February}, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Errorf("generate error = %v", err)
+			return
+		}
+		wiring := sheet.Days[0].Settings.Reflector.Wiring
+		if wiring == defaultWiring {
+			t.Errorf("default UKW-D wiring %s used", wiring)
+		}
+		if pairs := strings.Fields(wiring); len(pairs) != 12 || strings.ContainsAny(wiring, "JY") {
+			t.Errorf("invalid UKW-D wiring %s", wiring)
+		}
+		wirings[wiring] = true
+	}
+	if len(wirings) != 2 {
+		t.Errorf("want = %v\n got = %v", 2, len(wirings))
+	}
+}
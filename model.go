@@ -1,5 +1,7 @@
 package enigma
 
+import "fmt"
+
 type etwWiring string
 
 const (
@@ -20,54 +22,103 @@ const (
 	M4UKWD     Model = "M4-UKW-D"
 	SwissK     Model = "Swiss-K"
 	Tripitz    Model = "Tripitz"
+	G31        Model = "G-31"
+	G312       Model = "G-312"
+	G260       Model = "G-260"
+	Railway    Model = "Railway"
+	Norway     Model = "Norway"
 )
 
-// GetSupportedModels returns all the supported Enigma models
+// GetSupportedModels returns all the supported Enigma models (including the ones added by RegisterModel)
 func GetSupportedModels() []Model {
-	return []Model{
-		SwissK,
+	builtIn := []Model{
+		Commercial,
 		One,
 		M3,
 		M4,
 		M4UKWD,
 		SwissK,
 		Tripitz,
+		G31,
+		G312,
+		G260,
+		Railway,
+		Norway,
 	}
+	return append(builtIn, getRegisteredModels()...)
+}
+
+func (m Model) getDefinition() modelDefinition {
+	definition, _ := getModelDefinition(m)
+	return definition
 }
 
 func (m Model) exists() bool {
-	_, ok := models[m]
+	_, ok := getModelDefinition(m)
 	return ok
 }
 
 // GetName returns full name of this model
 func (m Model) GetName() string {
-	return models[m].name
+	return m.getDefinition().name
 }
 
 // GetDescription returns brief description of this model
 func (m Model) GetDescription() string {
-	return models[m].description
+	return m.getDefinition().description
 }
 
 // GetYear returns the year in which this model was first introduced
 func (m Model) GetYear() int {
-	return models[m].yearIntroduced
+	return m.getDefinition().yearIntroduced
+}
+
+// GetStepping returns the stepping mechanism of this model (lever stepping unless the model specifies otherwise)
+func (m Model) GetStepping() Stepping {
+	if stepping := m.getDefinition().stepping; stepping != nil {
+		return stepping
+	}
+	return LeverStepping
+}
+
+// GetAlphabet returns the letters of the keyboard of this model (the basic 26-letter alphabet for most of the models)
+func (m Model) GetAlphabet() string {
+	return m.getAlphabet().letterMap
+}
+
+// HasBasicAlphabet shows whether the keyboard of this model is the basic 26-letter Alphabet (A-Z)
+func (m Model) HasBasicAlphabet() bool {
+	return m.getAlphabet() == &Alphabet
+}
+
+// RequireBasicAlphabet returns ErrUnsupportedAlphabet if the keyboard of this model is not the basic Alphabet
+func (m Model) RequireBasicAlphabet() error {
+	if !m.HasBasicAlphabet() {
+		return fmt.Errorf("unsupported model %s: %w", m, ErrUnsupportedAlphabet)
+	}
+	return nil
+}
+
+func (m Model) getAlphabet() *alphabet {
+	if a := m.getDefinition().alphabet; a != nil {
+		return a
+	}
+	return &Alphabet
 }
 
 func (m Model) getEtwWiring() etwWiring {
-	return models[m].etw
+	return m.getDefinition().etw
 }
 
 // HasPlugboard shows if this model has a rewirable plugboard (not all Enigma models had that)
 func (m Model) HasPlugboard() bool {
-	return models[m].hasPlugboard
+	return m.getDefinition().hasPlugboard
 }
 
 // GetAvailableRotorSlots returns all the rotor slots available in this model
 func (m Model) GetAvailableRotorSlots() []RotorSlot {
 	// it is important that the slots are ordered right to left as this is the order the current flows through
-	if models[m].hasFourthRotor {
+	if m.getDefinition().hasFourthRotor {
 		return []RotorSlot{Right, Middle, Left, Fourth}
 	}
 	return []RotorSlot{Right, Middle, Left}
@@ -85,7 +136,7 @@ func (m Model) HasRotorSlot(slot RotorSlot) bool {
 
 // GetAvailableRotorModels returns the supported rotor set for this Enigma model
 func (m Model) GetAvailableRotorModels(slot RotorSlot) []RotorModel {
-	allAvailable := models[m].rotors
+	allAvailable := m.getDefinition().rotors
 	normal := make([]RotorModel, 0, len(allAvailable))
 	thin := make([]RotorModel, 0)
 	for _, rotorModel := range allAvailable {
@@ -102,6 +153,34 @@ func (m Model) GetAvailableRotorModels(slot RotorSlot) []RotorModel {
 	return normal
 }
 
+// GetRotorOrders returns all the valid rotor orders (placements of the available rotor models into all the slots) for this model
+func (m Model) GetRotorOrders() []map[RotorSlot]RotorModel {
+	slots := m.GetAvailableRotorSlots()
+	var result []map[RotorSlot]RotorModel
+	var fill func(i int, current map[RotorSlot]RotorModel, used map[RotorModel]bool)
+	fill = func(i int, current map[RotorSlot]RotorModel, used map[RotorModel]bool) {
+		if i < 0 {
+			order := make(map[RotorSlot]RotorModel, len(current))
+			for slot, rotorModel := range current {
+				order[slot] = rotorModel
+			}
+			result = append(result, order)
+			return
+		}
+		for _, rotorModel := range m.GetAvailableRotorModels(slots[i]) {
+			if used[rotorModel] {
+				continue // cannot use the same rotor model twice
+			}
+			used[rotorModel] = true
+			current[slots[i]] = rotorModel
+			fill(i-1, current, used)
+			used[rotorModel] = false
+		}
+	}
+	fill(len(slots)-1, map[RotorSlot]RotorModel{}, map[RotorModel]bool{}) // leftmost rotor first
+	return result
+}
+
 func (m Model) supportsRotorModel(rotorModel RotorModel, slot RotorSlot) bool {
 	for _, rot := range m.GetAvailableRotorModels(slot) {
 		if rot == rotorModel {
@@ -129,7 +208,7 @@ func (m Model) getDefaultRotorModels() map[RotorSlot]RotorModel {
 
 // GetAvailableReflectorModels return all the reflectors that can be plugged into this Enigma model
 func (m Model) GetAvailableReflectorModels() []ReflectorModel {
-	return models[m].reflectors
+	return m.getDefinition().reflectors
 }
 
 func (m Model) supportsReflectorModel(reflectorModel ReflectorModel) bool {
@@ -142,7 +221,11 @@ func (m Model) supportsReflectorModel(reflectorModel ReflectorModel) bool {
 }
 
 func (m Model) getDefaultReflectorModel() ReflectorModel {
-	return models[m].reflectors[0]
+	reflectors := m.getDefinition().reflectors
+	if len(reflectors) == 0 {
+		return "" // unsupported model
+	}
+	return reflectors[0]
 }
 
 type modelDefinition struct {
@@ -154,6 +237,8 @@ type modelDefinition struct {
 	reflectors     []ReflectorModel
 	rotors         []RotorModel
 	etw            etwWiring
+	stepping       Stepping  // LeverStepping if empty
+	alphabet       *alphabet // basic 26-letter Alphabet if empty
 }
 
 var models = map[Model]modelDefinition{
@@ -227,4 +312,57 @@ var models = map[Model]modelDefinition{
 		rotors:         []RotorModel{RotorIT, RotorIIT, RotorIIIT, RotorIVT, RotorVT, RotorVIT, RotorVIIT, RotorVIIIT},
 		etw:            etwTripitz,
 	},
+	G31: {
+		name:           "Enigma G-31",
+		description:    "Abwehr (military intelligence) version of the commercial Enigma, also known as the counter machine. Rotors were driven by cog wheels like an odometer, so there is no double stepping, but the rotors have many turnover notches and the settable reflector rotates as well. Uses the wiring of the commercial K model",
+		yearIntroduced: 1931,
+		hasPlugboard:   false,
+		hasFourthRotor: false,
+		reflectors:     []ReflectorModel{UkwG31},
+		rotors:         []RotorModel{RotorIG31, RotorIIG31, RotorIIIG31},
+		etw:            etwQwertz,
+		stepping:       GearStepping,
+	},
+	G312: {
+		name:           "Enigma G-312",
+		description:    "Variation of Enigma G-31 with rewired rotors and reflector, used by the Abwehr in the 1940s",
+		yearIntroduced: 1931,
+		hasPlugboard:   false,
+		hasFourthRotor: false,
+		reflectors:     []ReflectorModel{UkwG312},
+		rotors:         []RotorModel{RotorIG312, RotorIIG312, RotorIIIG312},
+		etw:            etwQwertz,
+		stepping:       GearStepping,
+	},
+	G260: {
+		name:           "Enigma G-260",
+		description:    "Variation of Enigma G-31 with rewired rotors, used by the Abwehr in Argentina",
+		yearIntroduced: 1931,
+		hasPlugboard:   false,
+		hasFourthRotor: false,
+		reflectors:     []ReflectorModel{UkwG260},
+		rotors:         []RotorModel{RotorIG260, RotorIIG260, RotorIIIG260},
+		etw:            etwQwertz,
+		stepping:       GearStepping,
+	},
+	Railway: {
+		name:           "Railway Enigma (Rocket)",
+		description:    "Variation of the commercial K model used by the German railways (Reichsbahn). Same mechanics with a movable reflector, but rewired rotors and reflector",
+		yearIntroduced: 1941,
+		hasPlugboard:   false,
+		hasFourthRotor: false,
+		reflectors:     []ReflectorModel{UkwR},
+		rotors:         []RotorModel{RotorIR, RotorIIR, RotorIIIR},
+		etw:            etwQwertz,
+	},
+	Norway: {
+		name:           "Norway Enigma (Norenigma)",
+		description:    "Enigma I machines used by the Norwegian police security service after WWII. The rotors and the reflector were rewired, otherwise identical to Enigma I",
+		yearIntroduced: 1945,
+		hasPlugboard:   true,
+		hasFourthRotor: false,
+		reflectors:     []ReflectorModel{UkwN},
+		rotors:         []RotorModel{RotorIN, RotorIIN, RotorIIIN, RotorIVN, RotorVN},
+		etw:            etwAbcdef,
+	},
 }
@@ -7,13 +7,23 @@ import (
 
 type plugboard struct {
 	isConfigurable bool
+	alphabet       *alphabet
 	letterMap      map[int]int
 }
 
-func newPlugboard(isConfigurable bool) plugboard {
+func newPlugboard(isConfigurable bool, a *alphabet) plugboard {
 	return plugboard{
 		isConfigurable: isConfigurable,
-		letterMap:      getDefaultLetterMap(),
+		alphabet:       a,
+		letterMap:      a.getDefaultLetterMap(),
+	}
+}
+
+func (pb *plugboard) clone() plugboard {
+	return plugboard{
+		isConfigurable: pb.isConfigurable,
+		alphabet:       pb.alphabet,
+		letterMap:      copyLetterMap(pb.letterMap),
 	}
 }
 
@@ -23,10 +33,10 @@ func (pb *plugboard) setup(plugConfig string) error {
 	}
 
 	// start with default map
-	letterMap := getDefaultLetterMap()
+	letterMap := pb.alphabet.getDefaultLetterMap()
 
 	// connect the plugs
-	pairs := strings.Split(plugConfig, " ")
+	pairs := strings.Fields(plugConfig) // empty config disconnects all the plugs
 	for _, pair := range pairs {
 		// validate the pair
 		if len(pair) != 2 {
@@ -38,7 +48,7 @@ func (pb *plugboard) setup(plugConfig string) error {
 		var letters [2]int
 		ok := false
 		for i := 0; i < 2; i++ {
-			letters[i], ok = Alphabet.charToInt(pair[i])
+			letters[i], ok = pb.alphabet.charToInt(pair[i])
 			if !ok {
 				return fmt.Errorf("invalid pair %s, unsupported letter %s", pair, string(pair[i]))
 			}
@@ -57,6 +67,17 @@ func (pb *plugboard) setup(plugConfig string) error {
 	return nil
 }
 
+// getPairs returns the connected plugs as letter pairs (in the same format as accepted by setup)
+func (pb *plugboard) getPairs() string {
+	pairs := make([]string, 0, pb.alphabet.getSize()/2)
+	for i := 0; i < pb.alphabet.getSize(); i++ {
+		if mapped := pb.letterMap[i]; mapped > i {
+			pairs = append(pairs, string([]byte{pb.alphabet.intToChar(i), pb.alphabet.intToChar(mapped)}))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
+
 func (pb *plugboard) translate(letter int) int {
 	return pb.letterMap[letter]
 }
@@ -0,0 +1,259 @@
+package enigma
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scheme converts the plaintext to the letters supported by Enigma and back, see Preprocess and Postprocess
+type Scheme interface {
+	Preprocess(text string) string
+	Postprocess(text string) string
+}
+
+// built-in schemes
+var (
+	DefaultScheme      Scheme = defaultScheme{} // spaces and basic punctuation replaced by double letters, lossless for such texts
+	WehrmachtScheme    Scheme = GermanScheme{}
+	KriegsmarineScheme Scheme = GermanScheme{Naval: true}
+)
+
+// Preprocess prepares the given text for Enigma encryption using the default scheme.
+// Unifies the text case and handles some common unsupported characters
+func Preprocess(text string) string {
+	return DefaultScheme.Preprocess(text)
+}
+
+// Postprocess converts preprocessed text back to readable format using the default scheme (complementary to Preprocess)
+func Postprocess(text string) string {
+	return DefaultScheme.Postprocess(text)
+}
+
+// PreprocessWith prepares the given text for Enigma encryption using the given scheme
+func PreprocessWith(scheme Scheme, text string) string {
+	return scheme.Preprocess(text)
+}
+
+// PostprocessWith converts the text preprocessed by the given scheme back to readable format
+func PostprocessWith(scheme Scheme, text string) string {
+	return scheme.Postprocess(text)
+}
+
+type defaultScheme struct{}
+
+// these are optimized for english language (the "to" letter pairs almost never occur in common english)
+var substitutions = []struct {
+	from string
+	to   string
+}{
+	{"Q ", "QW "},
+	{" Q", " WQ"},
+	{" ", "QQ"},
+	{"X,", "XW,"},
+	{",X", ",WX"},
+	{",", "XX"},
+	{"V.", "VW."},
+	{".V", ".WV"},
+	{".", "VV"},
+	{"Y-", "YQ-"},
+	{"-Y", "-QY"},
+	{"-", "YY"},
+}
+
+func (defaultScheme) Preprocess(text string) string {
+	text = strings.ToUpper(text) // convert to uppercase
+	// replace punctuations with double letters
+	for _, sub := range substitutions {
+		text = strings.ReplaceAll(text, sub.from, sub.to)
+	}
+
+	return text
+}
+
+func (defaultScheme) Postprocess(text string) string {
+	// convert the punctuations back
+	for i := len(substitutions) - 1; i >= 0; i-- {
+		text = strings.ReplaceAll(text, substitutions[i].to, substitutions[i].from)
+	}
+	return text
+}
+
+// GermanScheme implements the plaintext conventions of the German operators:
+//   - umlauts written as AE, OE, UE and ß as SS, CH as Q
+//   - X for a full stop, ZZ for a comma, XX for a colon, UD for a question mark and KK around parentheses, spaces left out
+//   - numbers spelled out digit by digit (Wehrmacht), or typed on the top row of the keyboard (Q=1 ... P=0) between Y (Kriegsmarine)
+//   - proper names written twice
+//
+// A real X is written as XQX so that it cannot be taken for a full stop, the Kriegsmarine scheme also writes a real Y twice
+// so that it cannot be taken for a number delimiter.
+// The conventions are lossy (words run together, Q can also be a Q), Postprocess restores only what can be told apart
+type GermanScheme struct {
+	Naval       bool     // numbers typed on the top row (Kriegsmarine)
+	ProperNames []string // names to be written twice
+}
+
+var (
+	germanLetters = strings.NewReplacer("Ä", "AE", "Ö", "OE", "Ü", "UE", "ß", "SS", "ẞ", "SS", "CH", "Q")
+	germanMarks   = strings.NewReplacer(".", "X", ",", "ZZ", ":", "XX", "?", "UD", "(", "KK", ")", "KK")
+	germanDigits  = []string{"NULL", "EINS", "ZWO", "DREI", "VIER", "FUENF", "SEQS", "SIEBEN", "AQT", "NEUN"}
+	germanSpacing = strings.NewReplacer(" .", ".", " ,", ",", " :", ":", " ?", "?", " )", ")", "( ", "(")
+	topRow        = "PQWERTZUIO" // digits 0-9 on the top row of the keyboard
+	germanX       = "XQX"        // real X, the single X is a full stop
+)
+
+// minSpelledDigits is the minimum number of the spelled out digits converted back to a number, the single digit words
+// are left as they are, as EINS, DREI or VIER also start the ordinary words (EINSATZ, DREIECK, VIERTEL)
+const minSpelledDigits = 2
+
+// Preprocess converts the text following the German conventions
+func (s GermanScheme) Preprocess(text string) string {
+	words := strings.Fields(strings.ToUpper(text))
+	for i, word := range words {
+		for _, name := range s.ProperNames {
+			name = strings.ToUpper(name)
+			if strings.TrimRightFunc(word, unicode.IsPunct) == name {
+				words[i] = name + word
+			}
+		}
+	}
+	text = germanLetters.Replace(strings.Join(words, ""))
+
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == 'X':
+			b.WriteString(germanX)
+		case r == 'Y' && s.Naval:
+			b.WriteString("YY")
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			start := i
+			for i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '9' {
+				i++
+			}
+			b.WriteString(s.writeNumber(string(runes[start : i+1])))
+		default:
+			b.WriteString(germanMarks.Replace(string(r))) // anything else is dropped
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 'A' || r > 'Z' {
+			return -1
+		}
+		return r
+	}, b.String())
+}
+
+func (s GermanScheme) writeNumber(digits string) string {
+	var b strings.Builder
+	if s.Naval {
+		b.WriteByte('Y')
+	}
+	for _, digit := range digits {
+		if s.Naval {
+			b.WriteByte(topRow[digit-'0'])
+		} else {
+			b.WriteString(germanDigits[digit-'0'])
+		}
+	}
+	if s.Naval {
+		b.WriteByte('Y')
+	}
+	return b.String()
+}
+
+// Postprocess makes the text readable again - numbers, punctuation, real X and Y, Q for CH and the doubled proper names
+func (s GermanScheme) Postprocess(text string) string {
+	for _, name := range s.ProperNames {
+		name = GermanScheme{Naval: s.Naval}.Preprocess(name) // written the same way as in the text, but only once
+		text = strings.ReplaceAll(text, name+name, " "+name+" ")
+	}
+
+	var b strings.Builder
+	parenthesis := false
+	for i := 0; i < len(text); i++ {
+		if number, length := s.readNumber(text[i:]); length > 0 {
+			b.WriteString(" " + number + " ")
+			i += length - 1
+			continue
+		}
+		next := byte(0)
+		if i+1 < len(text) {
+			next = text[i+1]
+		}
+		switch {
+		case strings.HasPrefix(text[i:], germanX):
+			b.WriteByte('X')
+			i += len(germanX) - 1
+		case s.Naval && text[i] == 'Y' && next == 'Y':
+			b.WriteByte('Y')
+			i++
+		case text[i] == 'K' && next == 'K':
+			if parenthesis {
+				b.WriteString(") ")
+			} else {
+				b.WriteString(" (")
+			}
+			parenthesis = !parenthesis
+			i++
+		case text[i] == 'X' && next == 'X':
+			b.WriteString(": ")
+			i++
+		case text[i] == 'Z' && next == 'Z':
+			b.WriteString(", ")
+			i++
+		case text[i] == 'U' && next == 'D':
+			b.WriteString("? ")
+			i++
+		case text[i] == 'X':
+			b.WriteString(". ")
+		case text[i] == 'Q' && next != 'U':
+			b.WriteString("CH")
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return germanSpacing.Replace(strings.Join(strings.Fields(b.String()), " "))
+}
+
+// readNumber reads the number written at the start of the text, returns the digits and the length of the written number
+// (zero if the text does not start with a number)
+func (s GermanScheme) readNumber(text string) (string, int) {
+	var digits []byte
+	if s.Naval {
+		if len(text) == 0 || text[0] != 'Y' {
+			return "", 0
+		}
+		end := strings.IndexByte(text[1:], 'Y')
+		if end <= 0 || strings.Trim(text[1:1+end], topRow) != "" {
+			return "", 0
+		}
+		for i := 1; i <= end; i++ {
+			digits = append(digits, byte('0'+strings.IndexByte(topRow, text[i])))
+		}
+		return string(digits), end + 2
+	}
+
+	length := 0
+	for {
+		found := false
+		for digit, word := range germanDigits {
+			if strings.HasPrefix(text[length:], word) {
+				digits = append(digits, byte('0'+digit))
+				length += len(word)
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	if len(digits) < minSpelledDigits {
+		return "", 0
+	}
+	return string(digits), length
+}
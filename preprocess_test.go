@@ -0,0 +1,113 @@
+package enigma
+
+import (
+	"testing"
+)
+
+func TestGermanScheme(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheme      Scheme
+		text        string
+		preprocess  string
+		postprocess string
+	}{
+		{
+			name:        "wehrmacht",
+			scheme:      GermanScheme{ProperNames: []string{"München"}},
+			text:        "Angriff auf München um 0600 Uhr, Richtung Süd.",
+			preprocess:  "ANGRIFFAUFMUENQENMUENQENUMNULLSEQSNULLNULLUHRZZRIQTUNGSUEDX",
+			postprocess: "ANGRIFFAUF MUENCHEN UM 0600 UHR, RICHTUNGSUED.",
+		},
+		{
+			name:        "kriegsmarine",
+			scheme:      KriegsmarineScheme,
+			text:        "Quadrat AJ 3874: Geleitzug (12 Schiffe).",
+			preprocess:  "QUADRATAJYEIURYXXGELEITZUGKKYQWYSQIFFEKKX",
+			postprocess: "QUADRATAJ 3874: GELEITZUG (12 SCHIFFE).",
+		},
+		{
+			name:        "wehrmacht real x and y",
+			scheme:      WehrmachtScheme,
+			text:        "Text an Major Meyer: Einsatz um 6 Uhr.",
+			preprocess:  "TEXQXTANMAJORMEYERXXEINSATZUMSEQSUHRX",
+			postprocess: "TEXTANMAJORMEYER: EINSATZUMSECHSUHR.",
+		},
+		{
+			name:        "kriegsmarine real x and y",
+			scheme:      KriegsmarineScheme,
+			text:        "Typ VIIC, Box 7 (Y treu Y).",
+			preprocess:  "TYYPVIICZZBOXQXYUYKKYYTREUYYKKX",
+			postprocess: "TYPVIIC, BOX 7 (YTREUY).",
+		},
+		{
+			name:        "ck kept",
+			scheme:      GermanScheme{},
+			text:        "Dicke Brücke",
+			preprocess:  "DICKEBRUECKE",
+			postprocess: "DICKEBRUECKE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preprocessed := PreprocessWith(tt.scheme, tt.text)
+			if preprocessed != tt.preprocess {
+				t.Errorf("want = %v\n got = %v", tt.preprocess, preprocessed)
+			}
+			e, _ := NewEnigma(M3)
+			if _, err := e.Encode(preprocessed); err != nil {
+				t.Errorf("encode error = %v", err)
+			}
+			if got := PostprocessWith(tt.scheme, preprocessed); got != tt.postprocess {
+				t.Errorf("want = %v\n got = %v", tt.postprocess, got)
+			}
+		})
+	}
+
+	// default scheme unchanged
+	if got, want := PreprocessWith(DefaultScheme, "Quiz, yes."), Preprocess("Quiz, yes."); got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
+
+func TestTransliterationScheme(t *testing.T) {
+	got := PreprocessWith(TransliterationScheme, "Ëlan of Łódź straße, room 12 [sic]")
+	if want := "ELANQQOFQQLODZQQSTRASSEXXQQROOMQQONETWOQQSIC"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+	if got, want := PostprocessWith(TransliterationScheme, got), "ELAN OF LODZ STRASSE, ROOM ONETWO SIC"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
+
+func TestLosslessScheme(t *testing.T) {
+	texts := []string{
+		"",
+		"Quick brown fox, QQ and qq.",
+		"Čeština: příliš žluťoučký kůň 🐎\n\ttabs {json: [1, 2]}",
+		"ZZZ xXx QZ QX QAB",
+		string([]byte{0xff, 0x00, 'A', 0x80}), // invalid UTF-8
+	}
+	e, err := createEnigma(M3, "I II III | B C D | 3 4 5", "C | |", "AB CD EF")
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	for _, text := range texts {
+		preprocessed := PreprocessWith(LosslessScheme, text)
+		e.RotorsReset()
+		encoded, err := e.Encode(preprocessed)
+		if err != nil {
+			t.Errorf("encode error = %v", err)
+			continue
+		}
+		e.RotorsReset()
+		decoded, _ := e.Encode(encoded)
+		if got := PostprocessWith(LosslessScheme, decoded); got != text {
+			t.Errorf("want = %q\n got = %q", text, got)
+		}
+	}
+	if got, want := PreprocessWith(LosslessScheme, "Hi, q"), "HQZIQCMQXQQ"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
@@ -0,0 +1,93 @@
+package procedure
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// BigramTable is the doubled-letter substitution table (Doppelbuchstabentauschtafel) disguising the naval indicator groups.
+// The tables are reciprocal, the same substitution packs and unpacks the indicators
+type BigramTable interface {
+	Substitute(bigram string) (string, error)
+}
+
+// ReciprocalTable is the bigram table pairing every bigram with another one
+type ReciprocalTable struct {
+	substitutions [alphabetSize * alphabetSize]int
+}
+
+// NewBigramTable creates the table from the given bigram pairs (each pair listed once, ie. "AA": "JX"),
+// all the 676 bigrams must be present exactly once
+func NewBigramTable(pairs map[string]string) (ReciprocalTable, error) {
+	table := ReciprocalTable{}
+	for i := range table.substitutions {
+		table.substitutions[i] = -1
+	}
+	for from, to := range pairs {
+		fromIndex, err := bigramToIndex(from)
+		if err != nil {
+			return ReciprocalTable{}, err
+		}
+		toIndex, err := bigramToIndex(to)
+		if err != nil {
+			return ReciprocalTable{}, err
+		}
+		if fromIndex == toIndex {
+			return ReciprocalTable{}, fmt.Errorf("bigram %s cannot be substituted by itself", from)
+		}
+		if table.substitutions[fromIndex] != -1 || table.substitutions[toIndex] != -1 {
+			return ReciprocalTable{}, fmt.Errorf("bigram pair %s-%s overlaps with another pair", from, to)
+		}
+		table.substitutions[fromIndex], table.substitutions[toIndex] = toIndex, fromIndex
+	}
+	for i, substitution := range table.substitutions {
+		if substitution == -1 {
+			return ReciprocalTable{}, fmt.Errorf("missing substitution for bigram %s", indexToBigram(i))
+		}
+	}
+	return table, nil
+}
+
+// NewRandomBigramTable generates a random valid table
+func NewRandomBigramTable(random *rand.Rand) ReciprocalTable {
+	table := ReciprocalTable{}
+	order := random.Perm(len(table.substitutions))
+	for i := 0; i < len(order); i += 2 {
+		table.substitutions[order[i]], table.substitutions[order[i+1]] = order[i+1], order[i]
+	}
+	return table
+}
+
+// Substitute returns the bigram paired with the given one
+func (t ReciprocalTable) Substitute(bigram string) (string, error) {
+	index, err := bigramToIndex(bigram)
+	if err != nil {
+		return "", err
+	}
+	return indexToBigram(t.substitutions[index]), nil
+}
+
+// String lists all the bigram pairs of the table, ie. "AA=JX AB=QW ..."
+func (t ReciprocalTable) String() string {
+	var pairs []string
+	for i, substitution := range t.substitutions {
+		if i < substitution {
+			pairs = append(pairs, indexToBigram(i)+"="+indexToBigram(substitution))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func bigramToIndex(bigram string) (int, error) {
+	if len(bigram) != 2 || bigram[0] < 'A' || bigram[0] > 'Z' || bigram[1] < 'A' || bigram[1] > 'Z' {
+		return 0, fmt.Errorf("invalid bigram \"%s\", must be two uppercase letters A-Z", bigram)
+	}
+	return int(bigram[0]-'A')*alphabetSize + int(bigram[1]-'A'), nil
+}
+
+func indexToBigram(index int) string {
+	return string([]byte{byte('A' + index/alphabetSize), byte('A' + index%alphabetSize)})
+}
@@ -0,0 +1,174 @@
+package procedure
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+)
+
+// indicatorLength is the length of the naval indicator - two groups of 4 letters
+const indicatorLength = 8
+
+// NavalOperator enciphers and deciphers the messages by the Kriegsmarine procedure (Schlüssel M).
+//
+// The operator picks a Kenngruppe (identifying the key net) from the Kenngruppenbuch and a random Verfahrenkenngruppe.
+// The Verfahrenkenngruppe enciphered at the ground setting of the key sheet gives the message key for the left, middle
+// and right rotor, the fourth rotor of M4 stays on its key sheet position. The two trigrams are written under each other
+// with a random filler letter ahead of the Kenngruppe and after the Verfahrenkenngruppe, the vertical bigrams are
+// substituted using the bigram table and the resulting two rows are sent ahead of and after the message text
+type NavalOperator struct {
+	settings    enigma.Settings
+	kenngruppen []string
+	table       BigramTable
+	call        string
+	random      *rand.Rand
+}
+
+// NewNavalOperator creates new naval operator, the settings are the key sheet of the day (the wheel positions are the ground
+// setting) and the kenngruppen are the indicator groups of the Kenngruppenbuch assigned to the key net
+func NewNavalOperator(settings enigma.Settings, kenngruppen []string, table BigramTable, call string, random *rand.Rand) (NavalOperator, error) {
+	if _, err := enigma.NewEnigmaFromSettings(settings); err != nil {
+		return NavalOperator{}, fmt.Errorf("invalid key sheet settings: %w", err)
+	}
+	if err := settings.Model.RequireBasicAlphabet(); err != nil {
+		return NavalOperator{}, err
+	}
+	if len(kenngruppen) == 0 {
+		return NavalOperator{}, fmt.Errorf("at least one Kenngruppe required")
+	}
+	for _, kenngruppe := range kenngruppen {
+		if !isTrigram(kenngruppe) {
+			return NavalOperator{}, fmt.Errorf("invalid Kenngruppe \"%s\", must be 3 uppercase letters A-Z", kenngruppe)
+		}
+	}
+	if table == nil {
+		return NavalOperator{}, fmt.Errorf("bigram table required")
+	}
+	if err := validateCall(call); err != nil {
+		return NavalOperator{}, err
+	}
+	if random == nil {
+		return NavalOperator{}, fmt.Errorf("random source required")
+	}
+	return NavalOperator{settings: settings, kenngruppen: kenngruppen, table: table, call: call, random: random}, nil
+}
+
+// Encipher enciphers the plaintext (letters A-Z only) to the complete message for the given recipient
+func (o NavalOperator) Encipher(to string, at time.Time, plaintext string) (Message, error) {
+	kenngruppe := o.kenngruppen[o.random.Intn(len(o.kenngruppen))]
+	verfahrenkenngruppe := toolkit.RandomLetters(o.random, 3)
+	indicator, err := o.PackIndicator(kenngruppe, verfahrenkenngruppe)
+	if err != nil {
+		return Message{}, err
+	}
+
+	e, err := o.setMessageKey(verfahrenkenngruppe)
+	if err != nil {
+		return Message{}, err
+	}
+	ciphertext, err := e.Encode(plaintext)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encipher the message: %w", err)
+	}
+
+	body := indicator + ciphertext + indicator
+	return Message{
+		Header: Header{From: o.call, To: to, Time: at.Format("1504"), Letters: len(body)},
+		Body:   body,
+	}, nil
+}
+
+// Decipher unpacks the indicator groups and deciphers the message body
+func (o NavalOperator) Decipher(message Message) (string, error) {
+	if message.Header.Letters != len(message.Body) {
+		return "", fmt.Errorf("letter count mismatch, header says %d, body has %d letters", message.Header.Letters, len(message.Body))
+	}
+	if len(message.Body) < 2*indicatorLength {
+		return "", fmt.Errorf("message body too short to contain the indicator groups")
+	}
+	indicator := message.Body[:indicatorLength]
+	if repeated := message.Body[len(message.Body)-indicatorLength:]; repeated != indicator {
+		return "", fmt.Errorf("indicator groups at the start (%s) and the end (%s) of the message differ", indicator, repeated)
+	}
+	_, verfahrenkenngruppe, err := o.UnpackIndicator(indicator)
+	if err != nil {
+		return "", err
+	}
+
+	e, err := o.setMessageKey(verfahrenkenngruppe)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := e.Encode(message.Body[indicatorLength : len(message.Body)-indicatorLength])
+	if err != nil {
+		return "", fmt.Errorf("failed to decipher the message: %w", err)
+	}
+	return plaintext, nil
+}
+
+// PackIndicator writes the Kenngruppe and Verfahrenkenngruppe to the two indicator groups using the bigram table
+func (o NavalOperator) PackIndicator(kenngruppe, verfahrenkenngruppe string) (string, error) {
+	if !isTrigram(kenngruppe) || !isTrigram(verfahrenkenngruppe) {
+		return "", fmt.Errorf("invalid indicator trigrams \"%s\" and \"%s\", must be 3 uppercase letters A-Z", kenngruppe, verfahrenkenngruppe)
+	}
+	top := toolkit.RandomLetters(o.random, 1) + kenngruppe
+	bottom := verfahrenkenngruppe + toolkit.RandomLetters(o.random, 1)
+	return o.substitute(top, bottom)
+}
+
+// UnpackIndicator recovers the Kenngruppe and Verfahrenkenngruppe from the two indicator groups
+func (o NavalOperator) UnpackIndicator(indicator string) (kenngruppe, verfahrenkenngruppe string, err error) {
+	if len(indicator) != indicatorLength {
+		return "", "", fmt.Errorf("invalid indicator \"%s\", must be %d letters", indicator, indicatorLength)
+	}
+	rows, err := o.substitute(indicator[:indicatorLength/2], indicator[indicatorLength/2:])
+	if err != nil {
+		return "", "", err
+	}
+	return rows[1 : indicatorLength/2], rows[indicatorLength/2 : indicatorLength-1], nil
+}
+
+// substitute replaces the vertical bigrams of the two rows, returns the two resulting rows joined
+func (o NavalOperator) substitute(top, bottom string) (string, error) {
+	result := make([]byte, len(top)+len(bottom))
+	for i := 0; i < len(top); i++ {
+		bigram, err := o.table.Substitute(string([]byte{top[i], bottom[i]}))
+		if err != nil {
+			return "", fmt.Errorf("bigram table substitution failed: %w", err)
+		}
+		result[i], result[len(top)+i] = bigram[0], bigram[1]
+	}
+	return string(result), nil
+}
+
+// setMessageKey creates the machine set to the message key - Verfahrenkenngruppe enciphered at the ground setting
+func (o NavalOperator) setMessageKey(verfahrenkenngruppe string) (enigma.Enigma, error) {
+	e, err := enigma.NewEnigmaFromSettings(o.settings)
+	if err != nil {
+		return enigma.Enigma{}, err
+	}
+	key, err := e.Encode(verfahrenkenngruppe)
+	if err != nil {
+		return enigma.Enigma{}, fmt.Errorf("failed to encipher the Verfahrenkenngruppe: %w", err)
+	}
+	slots := toolkit.GetSlots(o.settings.Model)
+	if err = toolkit.SetWheels(&e, slots[len(slots)-len(key):], key); err != nil {
+		return enigma.Enigma{}, err
+	}
+	return e, nil
+}
+
+func isTrigram(text string) bool {
+	if len(text) != 3 {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < 'A' || text[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
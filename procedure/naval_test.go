@@ -0,0 +1,120 @@
+package procedure
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestBigramTable(t *testing.T) {
+	table := NewRandomBigramTable(rand.New(rand.NewSource(1)))
+	for _, bigram := range []string{"AA", "QZ", "ZZ"} {
+		substituted, err := table.Substitute(bigram)
+		if err != nil {
+			t.Errorf("substitute error = %v", err)
+			return
+		}
+		if back, _ := table.Substitute(substituted); back != bigram || substituted == bigram {
+			t.Errorf("table not reciprocal for %s (%s -> %s)", bigram, substituted, back)
+		}
+	}
+	if _, err := table.Substitute("A1"); err == nil {
+		t.Errorf("expected invalid bigram error, got none")
+	}
+
+	// table listing can be loaded back
+	pairs := map[string]string{}
+	for _, pair := range strings.Fields(table.String()) {
+		pairs[pair[:2]] = pair[3:]
+	}
+	loaded, err := NewBigramTable(pairs)
+	if err != nil {
+		t.Errorf("table error = %v", err)
+		return
+	}
+	if loaded != table {
+		t.Errorf("loaded table differs from the original")
+	}
+
+	delete(pairs, "AA")
+	pairs["AB"] = "AB"
+	if _, err = NewBigramTable(pairs); err == nil {
+		t.Errorf("expected invalid table error, got none")
+	}
+}
+
+func TestNavalOperator(t *testing.T) {
+	settings, err := enigma.ParseSettings("M4 BThin beta-II-IV-I 01-01-01-22 VJNA AT BL DF GJ HM NW OP QY RZ VX")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	table := NewRandomBigramTable(rand.New(rand.NewSource(1)))
+	kenngruppen := []string{"SZQ", "XEY", "RKM"}
+	plaintext := "VONVONUUUBOOTXQUADRATXAAXSTOPXERBITTEWETTERBERICHT"
+
+	sender, err := NewNavalOperator(settings, kenngruppen, table, "UBOOT", rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Errorf("operator error = %v", err)
+		return
+	}
+	message, err := sender.Encipher("BDU", time.Date(1942, 3, 1, 8, 45, 0, 0, time.UTC), plaintext)
+	if err != nil {
+		t.Errorf("encipher error = %v", err)
+		return
+	}
+	if len(message.Body) != len(plaintext)+2*8 || message.Body[:8] != message.Body[len(message.Body)-8:] {
+		t.Errorf("invalid message body %s", message.Body)
+	}
+
+	receiver, _ := NewNavalOperator(settings, kenngruppen, table, "BDU", rand.New(rand.NewSource(3)))
+	kenngruppe, _, err := receiver.UnpackIndicator(message.Body[:8])
+	if err != nil {
+		t.Errorf("unpack error = %v", err)
+		return
+	}
+	if !strings.Contains(strings.Join(kenngruppen, " "), kenngruppe) {
+		t.Errorf("unpacked Kenngruppe %s not in the Kenngruppenbuch", kenngruppe)
+	}
+	got, err := receiver.Decipher(message)
+	if err != nil {
+		t.Errorf("decipher error = %v", err)
+		return
+	}
+	if got != plaintext {
+		t.Errorf("want = %v\n got = %v", plaintext, got)
+	}
+
+	// the trigrams survive the packing with a random filler
+	indicator, _ := sender.PackIndicator("SZQ", "RXQ")
+	if k, v, _ := receiver.UnpackIndicator(indicator); k != "SZQ" || v != "RXQ" {
+		t.Errorf("want = SZQ RXQ\n got = %s %s", k, v)
+	}
+
+	broken := message
+	broken.Body = "AAAAAAAA" + message.Body[8:]
+	if _, err = receiver.Decipher(broken); err == nil {
+		t.Errorf("expected indicator mismatch error, got none")
+	}
+	if _, err = NewNavalOperator(settings, []string{"SZ"}, table, "BDU", nil); err == nil {
+		t.Errorf("expected invalid Kenngruppe error, got none")
+	}
+	enigmatest.RegisterNumeric()
+	numeric, _ := enigma.ParseSettings(enigmatest.NumericSettings)
+	if _, err = NewNavalOperator(numeric, kenngruppen, table, "BDU", nil); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+	for _, call := range []string{"", "B U", "BDU="} {
+		if _, err = NewNavalOperator(settings, kenngruppen, table, call, rand.New(rand.NewSource(1))); err == nil {
+			t.Errorf("expected invalid call sign error for \"%s\", got none", call)
+		}
+	}
+	if _, err = NewNavalOperator(settings, kenngruppen, table, "BDU", nil); err == nil {
+		t.Errorf("expected random source error, got none")
+	}
+}
@@ -0,0 +1,214 @@
+// Package procedure implements the Wehrmacht operating procedures for enciphering and deciphering the messages.
+//
+// The operator sets the machine by the key sheet of the day, chooses a random message key, enciphers it (twice before 1940)
+// at the ground setting, resets the rotors to the message key and only then enciphers the text. The procedures differ
+// in where the ground setting comes from and how the enciphered message key (indicator) is transmitted:
+//   - Pre1938: ground setting from the key sheet, doubled indicator as the first 6 letters of the message body
+//   - Pre1940 (September 1938 - May 1940): ground setting chosen by the operator and sent in clear in the header,
+//     doubled indicator as the first 6 letters of the message body
+//   - Post1940: ground setting chosen by the operator, both the ground setting and the (single) indicator in the header
+//
+// The naval procedure with the Kenngruppen and bigram tables is implemented by the NavalOperator.
+package procedure
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+)
+
+const alphabetSize = enigma.AlphabetSize
+
+// Procedure specifies the message key procedure
+type Procedure int
+
+// supported procedures
+const (
+	Pre1938 Procedure = iota
+	Pre1940
+	Post1940
+)
+
+var procedureNames = map[Procedure]string{
+	Pre1938:  "pre-1938",
+	Pre1940:  "1938-1940",
+	Post1940: "post-1940",
+}
+
+// String returns the period the procedure was used in
+func (p Procedure) String() string {
+	if name, ok := procedureNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Procedure(%d)", int(p))
+}
+
+// isDoubled shows if the message key is enciphered twice (and sent as the first 6 letters of the message body)
+func (p Procedure) isDoubled() bool {
+	return p == Pre1938 || p == Pre1940
+}
+
+// Header is the clear part of the transmitted message
+type Header struct {
+	From, To  string // call signs
+	Time      string // time of origin, "HHMM"
+	Letters   int    // number of letters in the message body
+	Part      int    // part number of the messages split to parts (Teile), 0 if not split
+	Parts     int    // total number of parts, 0 if not split
+	Ground    string // ground setting chosen by the operator (1938 onwards)
+	Indicator string // enciphered message key (post-1940 only)
+}
+
+// Message is the complete transmitted message
+type Message struct {
+	Header Header
+	Body   string
+}
+
+// Operator enciphers and deciphers the messages by the given procedure and the key sheet of the day
+type Operator struct {
+	procedure Procedure
+	settings  enigma.Settings
+	call      string
+	random    *rand.Rand
+}
+
+// NewOperator creates new operator with the given call sign, the settings are the key sheet of the day
+// (the wheel positions are the ground setting for the pre-1938 procedure, ignored otherwise).
+// Message keys and ground settings are chosen using the given random source
+func NewOperator(procedure Procedure, settings enigma.Settings, call string, random *rand.Rand) (Operator, error) {
+	if _, ok := procedureNames[procedure]; !ok {
+		return Operator{}, fmt.Errorf("unsupported procedure %s", procedure)
+	}
+	if _, err := enigma.NewEnigmaFromSettings(settings); err != nil {
+		return Operator{}, fmt.Errorf("invalid key sheet settings: %w", err)
+	}
+	if err := settings.Model.RequireBasicAlphabet(); err != nil {
+		return Operator{}, err
+	}
+	if err := validateCall(call); err != nil {
+		return Operator{}, err
+	}
+	if random == nil {
+		return Operator{}, fmt.Errorf("random source required")
+	}
+	return Operator{procedure: procedure, settings: settings, call: call, random: random}, nil
+}
+
+// Encipher enciphers the plaintext (letters A-Z only) to the complete message for the given recipient
+func (o Operator) Encipher(to string, at time.Time, plaintext string) (Message, error) {
+	e, err := enigma.NewEnigmaFromSettings(o.settings)
+	if err != nil {
+		return Message{}, err
+	}
+	slots := toolkit.GetSlots(o.settings.Model)
+	header := Header{From: o.call, To: to, Time: at.Format("1504")}
+
+	ground := getInitialWheels(&e, slots)
+	if o.procedure != Pre1938 {
+		ground = toolkit.RandomLetters(o.random, len(slots))
+		header.Ground = ground
+	}
+	key := toolkit.RandomLetters(o.random, len(slots))
+	clearKey := key
+	if o.procedure.isDoubled() {
+		clearKey = key + key
+	}
+	if err = toolkit.SetWheels(&e, slots, ground); err != nil {
+		return Message{}, err
+	}
+	indicator, err := e.Encode(clearKey)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encipher the message key: %w", err)
+	}
+
+	if err = toolkit.SetWheels(&e, slots, key); err != nil {
+		return Message{}, err
+	}
+	ciphertext, err := e.Encode(plaintext)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encipher the message: %w", err)
+	}
+
+	body := ciphertext
+	if o.procedure.isDoubled() {
+		body = indicator + ciphertext
+	} else {
+		header.Indicator = indicator
+	}
+	header.Letters = len(body)
+	return Message{Header: header, Body: body}, nil
+}
+
+// Decipher recovers the message key from the indicator and deciphers the message body
+func (o Operator) Decipher(message Message) (string, error) {
+	e, err := enigma.NewEnigmaFromSettings(o.settings)
+	if err != nil {
+		return "", err
+	}
+	slots := toolkit.GetSlots(o.settings.Model)
+	if message.Header.Letters != len(message.Body) {
+		return "", fmt.Errorf("letter count mismatch, header says %d, body has %d letters", message.Header.Letters, len(message.Body))
+	}
+
+	ground := getInitialWheels(&e, slots)
+	if o.procedure != Pre1938 {
+		ground = message.Header.Ground
+	}
+	indicator, body := message.Header.Indicator, message.Body
+	if o.procedure.isDoubled() {
+		if len(body) < 2*len(slots) {
+			return "", fmt.Errorf("message body too short to contain the indicator")
+		}
+		indicator, body = body[:2*len(slots)], body[2*len(slots):]
+	}
+	if len(ground) != len(slots) {
+		return "", fmt.Errorf("invalid ground setting \"%s\", must have %d letters", ground, len(slots))
+	}
+	if err = toolkit.SetWheels(&e, slots, ground); err != nil {
+		return "", fmt.Errorf("invalid ground setting: %w", err)
+	}
+	key, err := e.Encode(indicator)
+	if err != nil {
+		return "", fmt.Errorf("invalid indicator: %w", err)
+	}
+	if o.procedure.isDoubled() {
+		if key[:len(slots)] != key[len(slots):] {
+			return "", fmt.Errorf("garbled indicator, the doubled message key %s does not repeat", key)
+		}
+		key = key[:len(slots)]
+	}
+	if len(key) != len(slots) {
+		return "", fmt.Errorf("invalid indicator \"%s\", must have %d letters", indicator, len(slots))
+	}
+
+	if err = toolkit.SetWheels(&e, slots, key); err != nil {
+		return "", err
+	}
+	plaintext, err := e.Encode(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to decipher the message: %w", err)
+	}
+	return plaintext, nil
+}
+
+// getInitialWheels returns the ground setting of the key sheet (initial wheel positions of the machine)
+func getInitialWheels(e *enigma.Enigma, slots []enigma.RotorSlot) string {
+	ground := make([]byte, len(slots))
+	for i, slot := range slots {
+		ground[i], _ = e.GetRotorInitialWheel(slot)
+	}
+	return string(ground)
+}
+
+// validateCall checks that the call sign can be written to the message header and parsed back (see ParseMessage)
+func validateCall(call string) error {
+	if call == "" || strings.ContainsAny(call, " =") {
+		return fmt.Errorf("invalid call sign \"%s\"", call)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package procedure
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestOperator(t *testing.T) {
+	settings, err := enigma.ParseSettings("I A II-I-III 24-13-22 KDR AB CD EF GH IJ KL")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	plaintext := "ANXOBERKOMMANDODERWEHRMACHTX"
+	at := time.Date(1939, 7, 25, 15, 10, 0, 0, time.UTC)
+
+	for _, procedure := range []Procedure{Pre1938, Pre1940, Post1940} {
+		t.Run(procedure.String(), func(t *testing.T) {
+			sender, err := NewOperator(procedure, settings, "C", rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Errorf("operator error = %v", err)
+				return
+			}
+			message, err := sender.Encipher("U6Z", at, plaintext)
+			if err != nil {
+				t.Errorf("encipher error = %v", err)
+				return
+			}
+			if message.Header.Time != "1510" || message.Header.Letters != len(message.Body) {
+				t.Errorf("invalid header %+v", message.Header)
+			}
+			wantBody := len(plaintext)
+			if procedure != Post1940 {
+				wantBody += 6
+			}
+			if len(message.Body) != wantBody {
+				t.Errorf("want = %v\n got = %v", wantBody, len(message.Body))
+			}
+			if (message.Header.Ground == "") != (procedure == Pre1938) || (message.Header.Indicator == "") != (procedure != Post1940) {
+				t.Errorf("invalid indicator in the header %+v", message.Header)
+			}
+
+			// transmit and decipher on the other end
+			received, err := ParseMessage(message.String())
+			if err != nil {
+				t.Errorf("parse error = %v", err)
+				return
+			}
+			if received != message {
+				t.Errorf("want = %+v\n got = %+v", message, received)
+			}
+			receiver, _ := NewOperator(procedure, settings, "U6Z", rand.New(rand.NewSource(2)))
+			got, err := receiver.Decipher(received)
+			if err != nil {
+				t.Errorf("decipher error = %v", err)
+				return
+			}
+			if got != plaintext {
+				t.Errorf("want = %v\n got = %v", plaintext, got)
+			}
+
+			// garbled doubled indicator is detected
+			if procedure != Post1940 {
+				garbled := received
+				body := []byte(garbled.Body)
+				body[0] = 'A' + (body[0]-'A'+1)%26
+				garbled.Body = string(body)
+				if _, err = receiver.Decipher(garbled); err == nil {
+					t.Errorf("expected garbled indicator error, got none")
+				}
+			}
+		})
+	}
+}
+
+func TestNewOperator_Alphabet(t *testing.T) {
+	enigmatest.RegisterNumeric()
+	settings, err := enigma.ParseSettings(enigmatest.NumericSettings)
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	if _, err = NewOperator(Post1940, settings, "C", rand.New(rand.NewSource(1))); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+}
+
+func TestNewOperator_Errors(t *testing.T) {
+	settings, err := enigma.ParseSettings("I A II-I-III 24-13-22 KDR AB CD EF GH IJ KL")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	testData := []struct {
+		name   string
+		call   string
+		random *rand.Rand
+	}{
+		{name: "empty call", call: "", random: rand.New(rand.NewSource(1))},
+		{name: "call with space", call: "C D", random: rand.New(rand.NewSource(1))},
+		{name: "call with separator", call: "C=", random: rand.New(rand.NewSource(1))},
+		{name: "nil random", call: "C", random: nil},
+	}
+	for _, item := range testData {
+		t.Run(item.name, func(t *testing.T) {
+			if _, err := NewOperator(Post1940, settings, item.call, item.random); err == nil {
+				t.Errorf("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	message, err := ParseMessage("U6Z DE C 1510 = 10 = EHZ TBS =\nABCDE FGHIJ\n")
+	if err != nil {
+		t.Errorf("parse error = %v", err)
+		return
+	}
+	want := Message{Header: Header{From: "C", To: "U6Z", Time: "1510", Letters: 10, Ground: "EHZ", Indicator: "TBS"}, Body: "ABCDEFGHIJ"}
+	if message != want {
+		t.Errorf("want = %+v\n got = %+v", want, message)
+	}
+
+	invalid := []string{
+		"U6Z DE C 1510 = 10 =\nABCDE",          // letter count mismatch
+		"U6Z C 1510 = 5 =\nABCDE",              // missing DE
+		"U6Z DE C 1510 = X =\nABCDE",           // invalid letter count
+		"U6Z DE C 1510 = 5 = A B C =\nABCDE",   // invalid indicator group
+		"U6Z DE C 1510 = 5 = EHZ = X =\nABCDE", // too many parts
+		strings.Repeat("garbage ", 3),
+	}
+	for _, text := range invalid {
+		if _, err = ParseMessage(text); err == nil {
+			t.Errorf("expected error for message %q, got none", text)
+		}
+	}
+}
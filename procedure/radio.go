@@ -0,0 +1,234 @@
+package procedure
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+)
+
+const (
+	// MaxPartLetters is the maximum number of letters of a single transmitted message, longer messages were split to parts (Teile)
+	MaxPartLetters = 250
+	// GroupSize is the number of letters in a group of the transmitted message
+	GroupSize = 5
+	// NavalGroupSize is the number of letters in a group of the naval messages
+	NavalGroupSize = 4
+
+	groupsPerLine = 10
+)
+
+// FormatGroups splits the text to groups of the given size (GroupSize if not positive), 10 groups per line
+func FormatGroups(text string, size int) string {
+	if size <= 0 {
+		size = GroupSize
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i += size {
+		if i > 0 {
+			if (i/size)%groupsPerLine == 0 {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		end := i + size
+		if end > len(text) {
+			end = len(text)
+		}
+		b.WriteString(text[i:end])
+	}
+	return b.String()
+}
+
+// CleanText turns the text copied from any source to a plain stream of uppercase letters A-Z,
+// dropping the group separators, line breaks, group numbers and any other characters
+func CleanText(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// String formats the message for transmission in groups of 5 letters, see Format
+func (m Message) String() string {
+	return m.Format(GroupSize)
+}
+
+// Format formats the message for transmission - header line followed by the body in groups of the given size (GroupSize if not positive),
+// ie. "U6Z DE C 1510 = 2TLE 1TL = 249 = EHZ TBS =" (parts and the indicators only present when used)
+func (m Message) Format(groupSize int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s DE %s %s =", m.Header.To, m.Header.From, m.Header.Time)
+	if m.Header.Parts > 0 {
+		fmt.Fprintf(&b, " %dTLE %dTL =", m.Header.Parts, m.Header.Part)
+	}
+	fmt.Fprintf(&b, " %d =", m.Header.Letters)
+	if m.Header.Ground != "" {
+		b.WriteString(" " + m.Header.Ground)
+		if m.Header.Indicator != "" {
+			b.WriteString(" " + m.Header.Indicator)
+		}
+		b.WriteString(" =")
+	}
+	b.WriteString("\n" + FormatGroups(m.Body, groupSize))
+	return b.String()
+}
+
+// ParseMessage parses the received message formatted by Message.Format (case-insensitive), the body is cleaned by CleanText
+func ParseMessage(text string) (Message, error) {
+	lines := strings.SplitN(strings.TrimSpace(text), "\n", 2)
+	headerLine, bodyText := strings.ToUpper(strings.TrimSpace(lines[0])), ""
+	if len(lines) > 1 {
+		bodyText = lines[1]
+	}
+	parts := strings.Split(headerLine, "=")
+	if len(parts) < 3 || strings.TrimSpace(parts[len(parts)-1]) != "" {
+		return Message{}, fmt.Errorf("invalid message header \"%s\"", headerLine)
+	}
+	parts = parts[:len(parts)-1]
+
+	var header Header
+	fields := strings.Fields(parts[0])
+	if len(fields) != 4 || fields[1] != "DE" {
+		return Message{}, fmt.Errorf("invalid message header \"%s\", must start with \"TO DE FROM TIME\"", headerLine)
+	}
+	header.To, header.From, header.Time = fields[0], fields[2], fields[3]
+	parts = parts[1:]
+
+	if len(parts) > 1 && strings.Contains(parts[0], "TL") {
+		if _, err := fmt.Sscanf(strings.TrimSpace(parts[0]), "%dTLE %dTL", &header.Parts, &header.Part); err != nil || header.Part < 1 || header.Part > header.Parts {
+			return Message{}, fmt.Errorf("invalid message parts \"%s\"", strings.TrimSpace(parts[0]))
+		}
+		parts = parts[1:]
+	}
+	letters, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return Message{}, fmt.Errorf("invalid letter count \"%s\"", strings.TrimSpace(parts[0]))
+	}
+	header.Letters = letters
+	switch len(parts) {
+	case 1:
+	case 2:
+		indicator := strings.Fields(parts[1])
+		switch len(indicator) {
+		case 2:
+			header.Indicator = indicator[1]
+			fallthrough
+		case 1:
+			header.Ground = indicator[0]
+		default:
+			return Message{}, fmt.Errorf("invalid indicator group \"%s\"", strings.TrimSpace(parts[1]))
+		}
+	default:
+		return Message{}, fmt.Errorf("invalid message header \"%s\"", headerLine)
+	}
+
+	body := CleanText(bodyText)
+	if len(body) != header.Letters {
+		return Message{}, fmt.Errorf("letter count mismatch, header says %d, body has %d letters", header.Letters, len(body))
+	}
+	return Message{Header: header, Body: body}, nil
+}
+
+// ParseMessages parses all the messages (or message parts) of the text, every message starts with its header line
+func ParseMessages(text string) ([]Message, error) {
+	var blocks []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, "=") {
+			blocks = append(blocks, line)
+		} else if len(blocks) > 0 {
+			blocks[len(blocks)-1] += "\n" + line
+		} else if strings.TrimSpace(line) != "" {
+			return nil, fmt.Errorf("text before the first message header: \"%s\"", line)
+		}
+	}
+	messages := make([]Message, len(blocks))
+	for i, block := range blocks {
+		message, err := ParseMessage(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", i+1, err)
+		}
+		messages[i] = message
+	}
+	return messages, nil
+}
+
+// EncipherParts enciphers the plaintext split to parts fitting the maximum message length, every part with its own message key
+func (o Operator) EncipherParts(to string, at time.Time, plaintext string) ([]Message, error) {
+	overhead := 0
+	if o.procedure.isDoubled() {
+		overhead = 2 * len(toolkit.GetSlots(o.settings.Model))
+	}
+	return encipherParts(plaintext, MaxPartLetters-overhead, func(part string) (Message, error) {
+		return o.Encipher(to, at, part)
+	})
+}
+
+// DecipherParts deciphers all the parts of the message and joins them in the order of their part numbers
+func (o Operator) DecipherParts(messages []Message) (string, error) {
+	return decipherParts(messages, o.Decipher)
+}
+
+// EncipherParts enciphers the plaintext split to parts fitting the maximum message length, every part with its own message key
+func (o NavalOperator) EncipherParts(to string, at time.Time, plaintext string) ([]Message, error) {
+	return encipherParts(plaintext, MaxPartLetters-2*indicatorLength, func(part string) (Message, error) {
+		return o.Encipher(to, at, part)
+	})
+}
+
+// DecipherParts deciphers all the parts of the message and joins them in the order of their part numbers
+func (o NavalOperator) DecipherParts(messages []Message) (string, error) {
+	return decipherParts(messages, o.Decipher)
+}
+
+func encipherParts(plaintext string, size int, encipher func(part string) (Message, error)) ([]Message, error) {
+	count := (len(plaintext) + size - 1) / size
+	if count <= 1 {
+		message, err := encipher(plaintext)
+		if err != nil {
+			return nil, err
+		}
+		return []Message{message}, nil
+	}
+
+	messages := make([]Message, count)
+	for i := range messages {
+		end := (i + 1) * size
+		if end > len(plaintext) {
+			end = len(plaintext)
+		}
+		message, err := encipher(plaintext[i*size : end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encipher part %d: %w", i+1, err)
+		}
+		message.Header.Part, message.Header.Parts = i+1, count
+		messages[i] = message
+	}
+	return messages, nil
+}
+
+func decipherParts(messages []Message, decipher func(message Message) (string, error)) (string, error) {
+	sorted := append([]Message(nil), messages...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Header.Part < sorted[j].Header.Part
+	})
+	var b strings.Builder
+	for i, message := range sorted {
+		if (len(sorted) > 1 || message.Header.Parts > 1) && (message.Header.Part != i+1 || message.Header.Parts != len(sorted)) {
+			return "", fmt.Errorf("incomplete message, got %d of %d parts", len(sorted), message.Header.Parts)
+		}
+		plaintext, err := decipher(message)
+		if err != nil {
+			return "", fmt.Errorf("failed to decipher part %d: %w", message.Header.Part, err)
+		}
+		b.WriteString(plaintext)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,103 @@
+package procedure
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+func TestFormatGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		size int
+		want string
+	}{
+		{"empty", "", 5, ""},
+		{"incomplete group", "ABCDEFG", 5, "ABCDE FG"},
+		{"naval", "ABCDEFGH", 4, "ABCD EFGH"},
+		{"line break", strings.Repeat("A", 55), 5, strings.TrimSpace(strings.Repeat("AAAAA ", 10)) + "\nAAAAA"},
+		{"zero size", "ABCDEFG", 0, "ABCDE FG"},
+		{"negative size", "ABCDEFG", -1, "ABCDE FG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatGroups(tt.text, tt.size); got != tt.want {
+				t.Errorf("want = %v\n got = %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	pasted := "1 = nczwv usxpn yminh zxmqx\n2 = SFWDK  OOXWR,\tLUDKF ä 3"
+	if got, want := CleanText(pasted), "NCZWVUSXPNYMINHZXMQXSFWDKOOXWRLUDKF"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
+
+func TestEncipherParts(t *testing.T) {
+	settings, _ := enigma.ParseSettings("I A II-I-III 24-13-22 KDR AB CD EF GH IJ KL")
+	operator, err := NewOperator(Pre1940, settings, "C", rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Errorf("operator error = %v", err)
+		return
+	}
+	plaintext := strings.Repeat("ANXOBERKOMMANDODERWEHRMACHT", 20)
+	messages, err := operator.EncipherParts("U6Z", time.Date(1939, 7, 25, 15, 10, 0, 0, time.UTC), plaintext)
+	if err != nil {
+		t.Errorf("encipher error = %v", err)
+		return
+	}
+	if len(messages) != 3 {
+		t.Errorf("want = %v\n got = %v", 3, len(messages))
+	}
+	var transmitted []string
+	for _, message := range messages {
+		if message.Header.Letters > MaxPartLetters || message.Header.Parts != 3 {
+			t.Errorf("invalid part header %+v", message.Header)
+		}
+		transmitted = append(transmitted, message.String())
+	}
+	if !strings.HasPrefix(transmitted[1], "U6Z DE C 1510 = 3TLE 2TL = 250 = ") {
+		t.Errorf("invalid part header line %s", strings.SplitN(transmitted[1], "\n", 2)[0])
+	}
+
+	// parts received in a different order, with messy formatting
+	received, err := ParseMessages(strings.ToLower(transmitted[2]) + "\n\n" + transmitted[0] + "\n" + strings.ReplaceAll(transmitted[1], " ", "  "))
+	if err != nil {
+		t.Errorf("parse error = %v", err)
+		return
+	}
+	got, err := operator.DecipherParts(received)
+	if err != nil {
+		t.Errorf("decipher error = %v", err)
+		return
+	}
+	if got != plaintext {
+		t.Errorf("want = %v\n got = %v", plaintext, got)
+	}
+	if _, err = operator.DecipherParts(received[:2]); err == nil {
+		t.Errorf("expected missing part error, got none")
+	}
+
+	// naval messages in groups of 4
+	naval, _ := enigma.ParseSettings("M4 BThin beta-II-IV-I 01-01-01-22 VJNA AT BL DF GJ HM NW OP QY RZ VX")
+	navalOperator, _ := NewNavalOperator(naval, []string{"SZQ"}, NewRandomBigramTable(rand.New(rand.NewSource(1))), "UBOOT", rand.New(rand.NewSource(2)))
+	navalMessages, err := navalOperator.EncipherParts("BDU", time.Now(), plaintext)
+	if err != nil {
+		t.Errorf("encipher error = %v", err)
+		return
+	}
+	formatted := navalMessages[0].Format(NavalGroupSize)
+	if body := strings.SplitN(formatted, "\n", 2)[1]; len(strings.Fields(body)[0]) != 4 {
+		t.Errorf("naval message not in groups of 4:\n%s", formatted)
+	}
+	parsed, _ := ParseMessage(formatted)
+	if got, _ := navalOperator.DecipherParts(append([]Message{parsed}, navalMessages[1:]...)); got != plaintext {
+		t.Errorf("want = %v\n got = %v", plaintext, got)
+	}
+}
@@ -2,6 +2,7 @@ package enigma
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -16,21 +17,26 @@ func (r ReflectorConfig) isEmpty() bool {
 	return r.Model == "" && r.WheelPosition == 0 && r.Wiring == ""
 }
 
+// UKW-D rewirable reflectors had different letter order (JY were always connected, the rest 12 pairs were configurable)
+const ukwdOrder = "AJZXWVUTSRQPONYMLKIHGFEDCB"
+
 type reflector struct {
-	model         ReflectorModel
-	letterMap     map[int]int
-	wheelPosition int
+	model                ReflectorModel
+	alphabet             *alphabet
+	letterMap            map[int]int
+	initialWheelPosition int // necessary for reset of the stepping reflectors
+	wheelPosition        int
 }
 
-func newReflector(model ReflectorModel) reflector {
+func newReflector(model ReflectorModel, a *alphabet) reflector {
 	wiring := model.getWiring()
-	if !Alphabet.isValidWiring(wiring) {
+	if !a.isValidWiring(wiring) {
 		panic(fmt.Errorf("invalid reflector wiring %s", wiring))
 	}
 
-	letterMap := make(map[int]int, Alphabet.getSize())
+	letterMap := make(map[int]int, a.getSize())
 	for i, letter := range wiring {
-		letterIndex, ok := Alphabet.charToInt(byte(letter))
+		letterIndex, ok := a.charToInt(byte(letter))
 		if !ok {
 			panic(fmt.Errorf("unsupported wiring letter %s", string(letter))) // should not happen, we already checked the wiring validity
 		}
@@ -39,22 +45,31 @@ func newReflector(model ReflectorModel) reflector {
 	}
 
 	return reflector{
-		model:         model,
-		letterMap:     letterMap,
-		wheelPosition: 0,
+		model:                model,
+		alphabet:             a,
+		letterMap:            letterMap,
+		initialWheelPosition: 0,
+		wheelPosition:        0,
 	}
 }
 
+func (r *reflector) clone() reflector {
+	result := *r
+	result.letterMap = copyLetterMap(r.letterMap)
+	return result
+}
+
 func (r *reflector) setWheelPosition(letter byte) error {
 	if !r.model.IsMovable() {
 		return fmt.Errorf("reflector %s is fixed, cannot change position", r.model)
 	}
-	index, ok := Alphabet.charToInt(letter)
+	index, ok := r.alphabet.charToInt(letter)
 	if !ok {
 		return fmt.Errorf("unsupported reflector position %s", string(letter))
 	}
 
 	r.wheelPosition = index
+	r.initialWheelPosition = index
 	return nil
 }
 
@@ -62,16 +77,17 @@ func (r *reflector) setWiring(wiring string) error {
 	if !r.model.IsRewirable() {
 		return fmt.Errorf("reflector %s is not rewirable, cannot change wiring", r.model)
 	}
+	if r.alphabet.getSize() != len(ukwdOrder) {
+		return fmt.Errorf("reflector %s can only be rewired with the basic alphabet", r.model)
+	}
 
-	// UKW-D rewirable reflectors had different letter order (JY were always connected, the rest 12 pairs were configurable)
-	ukwdOrder := "AJZXWVUTSRQPONYMLKIHGFEDCB"
-	wiringMap := getDefaultLetterMap()
+	wiringMap := r.alphabet.getDefaultLetterMap()
 	wiringMap[strings.IndexByte(ukwdOrder, 'J')] = strings.IndexByte(ukwdOrder, 'Y')
 	wiringMap[strings.IndexByte(ukwdOrder, 'Y')] = strings.IndexByte(ukwdOrder, 'J')
 
 	// rewire the reflector
 	pairs := strings.Split(wiring, " ")
-	expectedSize := Alphabet.getSize()/2 - 1
+	expectedSize := r.alphabet.getSize()/2 - 1
 	if len(pairs) != expectedSize {
 		return fmt.Errorf("incomplete wiring of the reflector, must include %d distinct pairs to cover the whole alphabet", expectedSize)
 	}
@@ -107,7 +123,44 @@ func (r *reflector) setWiring(wiring string) error {
 	return nil
 }
 
+func (r *reflector) reset() {
+	r.wheelPosition = r.initialWheelPosition
+}
+
+func (r *reflector) rotate() {
+	r.wheelPosition = (r.wheelPosition + 1) % r.alphabet.getSize()
+}
+
+func (r *reflector) getWheelPosition() byte {
+	return r.alphabet.intToChar(r.wheelPosition)
+}
+
+func (r *reflector) getInitialWheelPosition() byte {
+	return r.alphabet.intToChar(r.initialWheelPosition)
+}
+
+// getWiring returns the current wiring of a rewirable reflector as letter pairs (in the same format as accepted by setWiring)
+func (r *reflector) getWiring() string {
+	if !r.model.IsRewirable() {
+		return ""
+	}
+	pairs := make([]string, 0, r.alphabet.getSize()/2-1)
+	for i := 0; i < r.alphabet.getSize(); i++ {
+		mapped := r.letterMap[i]
+		if mapped <= i || ukwdOrder[i] == 'J' || ukwdOrder[i] == 'Y' {
+			continue // each pair only once and without the hard-wired JY pair
+		}
+		pair := []byte{ukwdOrder[i], ukwdOrder[mapped]}
+		if pair[0] > pair[1] {
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		pairs = append(pairs, string(pair))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
 func (r *reflector) translate(input int) int {
-	rotatedOutput := r.letterMap[shift(input, r.wheelPosition)]
-	return shift(rotatedOutput, -r.wheelPosition) // don't forget to rotate back...
+	rotatedOutput := r.letterMap[r.alphabet.shift(input, r.wheelPosition)]
+	return r.alphabet.shift(rotatedOutput, -r.wheelPosition) // don't forget to rotate back...
 }
@@ -13,26 +13,35 @@ const (
 	UkwCThin ReflectorModel = "CThin"
 	UkwD     ReflectorModel = "D"
 	UkwT     ReflectorModel = "T"
+	UkwG31   ReflectorModel = "G31"
+	UkwG312  ReflectorModel = "G312"
+	UkwG260  ReflectorModel = "G260"
+	UkwR     ReflectorModel = "R"
+	UkwN     ReflectorModel = "N"
 )
 
 // IsThin shows whether this reflector model is thin, or normal size,
 // only thin reflectors can fit to the small slot in 4-rotor Enigma model
 func (r ReflectorModel) IsThin() bool {
-	return reflectorDefinitions[r].isThin
+	definition, _ := getReflectorDefinition(r)
+	return definition.isThin
 }
 
 // IsMovable shows if this reflector model can rotate
 func (r ReflectorModel) IsMovable() bool {
-	return reflectorDefinitions[r].isMovable
+	definition, _ := getReflectorDefinition(r)
+	return definition.isMovable
 }
 
 // IsRewirable shows if this reflector model can be custom-rewired
 func (r ReflectorModel) IsRewirable() bool {
-	return reflectorDefinitions[r].isRewirable
+	definition, _ := getReflectorDefinition(r)
+	return definition.isRewirable
 }
 
 func (r ReflectorModel) getWiring() string {
-	return reflectorDefinitions[r].wiring
+	definition, _ := getReflectorDefinition(r)
+	return definition.wiring
 }
 
 type reflectorDefinition struct {
@@ -40,6 +49,14 @@ type reflectorDefinition struct {
 	isMovable   bool
 	isThin      bool
 	wiring      string
+	alphabet    *alphabet // basic Alphabet if empty
+}
+
+func (d reflectorDefinition) getAlphabet() *alphabet {
+	if d.alphabet != nil {
+		return d.alphabet
+	}
+	return &Alphabet
 }
 
 var reflectorDefinitions = map[ReflectorModel]reflectorDefinition{
@@ -91,4 +108,34 @@ var reflectorDefinitions = map[ReflectorModel]reflectorDefinition{
 		isThin:      false,
 		wiring:      "GEKPBTAUMOCNILJDXZYFHWVQSR",
 	},
+	UkwG31: {
+		isRewirable: false,
+		isMovable:   true,
+		isThin:      false,
+		wiring:      "IMETCGFRAYSQBZXWLHKDVUPOJN",
+	},
+	UkwG312: {
+		isRewirable: false,
+		isMovable:   true,
+		isThin:      false,
+		wiring:      "RULQMZJSYGOCETKWDAHNBXPVIF",
+	},
+	UkwG260: {
+		isRewirable: false,
+		isMovable:   true,
+		isThin:      false,
+		wiring:      "IMETCGFRAYSQBZXWLHKDVUPOJN",
+	},
+	UkwR: {
+		isRewirable: false,
+		isMovable:   true,
+		isThin:      false,
+		wiring:      "QYHOGNECVPUZTFDJAXWMKISRBL",
+	},
+	UkwN: {
+		isRewirable: false,
+		isMovable:   false,
+		isThin:      false,
+		wiring:      "MOWJYPUXNDSRAIBFVLKZGQCHET",
+	},
 }
@@ -0,0 +1,238 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// RotorDefinition specifies a custom rotor model for RegisterRotor
+type RotorDefinition struct {
+	Wiring         string // letters the alphabet (ABC...) is wired to, ie. "EKMFLGDQVZNTOWYHXUSPAIBRCJ"
+	NotchPositions string // wheel positions (letters in the window) from which the rotor steps the next rotor
+	IsThin         bool   // thin rotors only fit the fourth slot
+	Alphabet       string // letters of the rotor contacts, basic Alphabet if empty
+}
+
+// ReflectorDefinition specifies a custom reflector model for RegisterReflector
+type ReflectorDefinition struct {
+	Wiring      string // letters the alphabet (ABC...) is wired to, must connect the letters in pairs
+	IsMovable   bool   // movable reflectors can be set to any position (and stepped by the gear stepping)
+	IsThin      bool   // thin reflectors fit the 4-rotor models
+	IsRewirable bool   // rewirable reflectors are rewired in the same way as UKW-D (basic Alphabet only)
+	Alphabet    string // letters of the reflector contacts, basic Alphabet if empty
+}
+
+// ModelDefinition specifies a custom Enigma model for RegisterModel
+type ModelDefinition struct {
+	Name           string
+	Description    string
+	YearIntroduced int
+	HasPlugboard   bool
+	HasFourthRotor bool
+	Reflectors     []ReflectorModel // the first one is the default
+	Rotors         []RotorModel
+	EtwWiring      string   // letters the keyboard (ABC...) is wired to, no ETW scrambling if empty
+	Stepping       Stepping // LeverStepping if empty
+	Alphabet       string   // letters of the keyboard, basic Alphabet if empty (must be the same for all the rotors and reflectors)
+}
+
+// definitionsLock guards the models and the rotor and reflector definitions which can be extended at runtime
+var definitionsLock sync.RWMutex
+
+// registeredModels keeps the order in which the custom models were registered
+var registeredModels []Model
+
+// RegisterRotor adds a custom rotor model, making it available for the custom models added by RegisterModel
+func RegisterRotor(model RotorModel, definition RotorDefinition) error {
+	if err := validateName(string(model)); err != nil {
+		return fmt.Errorf("invalid rotor model: %w", err)
+	}
+	a, err := parseAlphabet(definition.Alphabet)
+	if err != nil {
+		return fmt.Errorf("invalid rotor %s: %w", model, err)
+	}
+	if !a.isValidWiring(definition.Wiring) {
+		return fmt.Errorf("invalid wiring %s of rotor %s, must contain each letter of the alphabet exactly once", definition.Wiring, model)
+	}
+	for i := range definition.NotchPositions {
+		if _, ok := a.charToInt(definition.NotchPositions[i]); !ok {
+			return fmt.Errorf("invalid notch position %s of rotor %s", string(definition.NotchPositions[i]), model)
+		}
+	}
+
+	definitionsLock.Lock()
+	defer definitionsLock.Unlock()
+	if _, ok := rotorDefinitions[model]; ok {
+		return fmt.Errorf("rotor model %s already exists", model)
+	}
+	rotorDefinitions[model] = rotorDefinition{
+		notchPositions: []byte(definition.NotchPositions),
+		isThin:         definition.IsThin,
+		wiring:         definition.Wiring,
+		alphabet:       a,
+	}
+	return nil
+}
+
+// RegisterReflector adds a custom reflector model, making it available for the custom models added by RegisterModel
+func RegisterReflector(model ReflectorModel, definition ReflectorDefinition) error {
+	if err := validateName(string(model)); err != nil {
+		return fmt.Errorf("invalid reflector model: %w", err)
+	}
+	if strings.ContainsAny(string(model), "@=") {
+		return fmt.Errorf("invalid reflector model %s, cannot contain @ or =", model)
+	}
+	a, err := parseAlphabet(definition.Alphabet)
+	if err != nil {
+		return fmt.Errorf("invalid reflector %s: %w", model, err)
+	}
+	if definition.IsRewirable && a != &Alphabet {
+		return fmt.Errorf("invalid reflector %s, rewirable reflectors only support the basic alphabet", model)
+	}
+	if !a.isValidWiring(definition.Wiring) {
+		return fmt.Errorf("invalid wiring %s of reflector %s, must contain each letter of the alphabet exactly once", definition.Wiring, model)
+	}
+	for i := range definition.Wiring {
+		mapped, _ := a.charToInt(definition.Wiring[i])
+		if mapped == i || a.intToChar(i) != definition.Wiring[mapped] {
+			return fmt.Errorf("invalid wiring %s of reflector %s, must connect the letters in pairs", definition.Wiring, model)
+		}
+	}
+
+	definitionsLock.Lock()
+	defer definitionsLock.Unlock()
+	if _, ok := reflectorDefinitions[model]; ok {
+		return fmt.Errorf("reflector model %s already exists", model)
+	}
+	reflectorDefinitions[model] = reflectorDefinition{
+		isRewirable: definition.IsRewirable,
+		isMovable:   definition.IsMovable,
+		isThin:      definition.IsThin,
+		wiring:      definition.Wiring,
+		alphabet:    a,
+	}
+	return nil
+}
+
+// RegisterModel adds a custom Enigma model built from the existing (or registered) rotors and reflectors.
+// Registered models can be used everywhere the built-in ones can, including the settings parsing
+func RegisterModel(model Model, definition ModelDefinition) error {
+	if err := validateName(string(model)); err != nil {
+		return fmt.Errorf("invalid model: %w", err)
+	}
+	if definition.Name == "" {
+		definition.Name = string(model)
+	}
+	a, err := parseAlphabet(definition.Alphabet)
+	if err != nil {
+		return fmt.Errorf("invalid model %s: %w", model, err)
+	}
+	if definition.EtwWiring == "" {
+		definition.EtwWiring = a.letterMap
+	}
+	if !a.isValidWiring(definition.EtwWiring) {
+		return fmt.Errorf("invalid ETW wiring %s of model %s, must contain each letter of the alphabet exactly once", definition.EtwWiring, model)
+	}
+	if len(definition.Reflectors) == 0 {
+		return fmt.Errorf("model %s must have at least one reflector", model)
+	}
+
+	definitionsLock.Lock()
+	defer definitionsLock.Unlock()
+	if _, ok := models[model]; ok {
+		return fmt.Errorf("model %s already exists", model)
+	}
+	for _, reflectorModel := range definition.Reflectors {
+		reflector, ok := reflectorDefinitions[reflectorModel]
+		if !ok {
+			return fmt.Errorf("unsupported reflector model %s", reflectorModel)
+		}
+		if reflector.getAlphabet().letterMap != a.letterMap {
+			return fmt.Errorf("reflector %s does not match the alphabet of model %s", reflectorModel, model)
+		}
+	}
+	normal, thin := 0, 0
+	for _, rotorModel := range definition.Rotors {
+		rotor, ok := rotorDefinitions[rotorModel]
+		if !ok {
+			return fmt.Errorf("unsupported rotor model %s", rotorModel)
+		}
+		if rotor.getAlphabet().letterMap != a.letterMap {
+			return fmt.Errorf("rotor %s does not match the alphabet of model %s", rotorModel, model)
+		}
+		if rotor.isThin {
+			thin++
+		} else {
+			normal++
+		}
+	}
+	if normal < 3 {
+		return fmt.Errorf("model %s must have at least 3 normal rotors", model)
+	}
+	if definition.HasFourthRotor && thin == 0 {
+		return fmt.Errorf("model %s with the fourth rotor must have at least one thin rotor", model)
+	}
+	if definition.Stepping != nil {
+		rotorCount := 3
+		if definition.HasFourthRotor {
+			rotorCount = 4
+		}
+		if err := validateStepping(definition.Stepping, rotorCount); err != nil {
+			return fmt.Errorf("invalid stepping of model %s: %w", model, err)
+		}
+	}
+
+	models[model] = modelDefinition{
+		name:           definition.Name,
+		description:    definition.Description,
+		yearIntroduced: definition.YearIntroduced,
+		hasPlugboard:   definition.HasPlugboard,
+		hasFourthRotor: definition.HasFourthRotor,
+		reflectors:     append([]ReflectorModel(nil), definition.Reflectors...),
+		rotors:         append([]RotorModel(nil), definition.Rotors...),
+		etw:            etwWiring(definition.EtwWiring),
+		stepping:       definition.Stepping,
+		alphabet:       a,
+	}
+	registeredModels = append(registeredModels, model)
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if strings.IndexFunc(name, unicode.IsSpace) != -1 {
+		return fmt.Errorf("name %q cannot contain spaces", name)
+	}
+	return nil
+}
+
+func getModelDefinition(model Model) (modelDefinition, bool) {
+	definitionsLock.RLock()
+	defer definitionsLock.RUnlock()
+	definition, ok := models[model]
+	return definition, ok
+}
+
+func getRotorDefinition(model RotorModel) (rotorDefinition, bool) {
+	definitionsLock.RLock()
+	defer definitionsLock.RUnlock()
+	definition, ok := rotorDefinitions[model]
+	return definition, ok
+}
+
+func getReflectorDefinition(model ReflectorModel) (reflectorDefinition, bool) {
+	definitionsLock.RLock()
+	defer definitionsLock.RUnlock()
+	definition, ok := reflectorDefinitions[model]
+	return definition, ok
+}
+
+func getRegisteredModels() []Model {
+	definitionsLock.RLock()
+	defer definitionsLock.RUnlock()
+	return append([]Model(nil), registeredModels...)
+}
@@ -0,0 +1,205 @@
+package enigma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegisterModel(t *testing.T) {
+	rotors := map[RotorModel]RotorDefinition{
+		"I-Test":    {Wiring: "JCRBYAIUVZDOQWTMXHGFKSLPNE", NotchPositions: "Q"},
+		"II-Test-X": {Wiring: "UDNCQXTYIWBOMHLPFVJZKRESAG", NotchPositions: "EM"},
+		"III-Test":  {Wiring: "ELTQVMKBPDZUNJIOHWYFSXRCGA", NotchPositions: "V"},
+	}
+	for model, definition := range rotors {
+		if err := RegisterRotor(model, definition); err != nil {
+			t.Errorf("register rotor error = %v", err)
+			return
+		}
+	}
+	if err := RegisterReflector("Test", ReflectorDefinition{Wiring: "FOWULAQYSRTEZVBXGJIKDNCPHM", IsRewirable: true}); err != nil {
+		t.Errorf("register reflector error = %v", err)
+		return
+	}
+	err := RegisterModel("Test-KD", ModelDefinition{
+		Name:           "Test KD",
+		YearIntroduced: 1944,
+		Reflectors:     []ReflectorModel{"Test"},
+		Rotors:         []RotorModel{"I-Test", "II-Test-X", "III-Test"},
+		EtwWiring:      etwQwertz,
+	})
+	if err != nil {
+		t.Errorf("register model error = %v", err)
+		return
+	}
+
+	found := false
+	for _, model := range GetSupportedModels() {
+		found = found || model == "Test-KD"
+	}
+	if !found {
+		t.Errorf("registered model not among the supported models")
+	}
+
+	settings, err := ParseSettings("Test-KD Test=AQBGCKDIELFXHZMWNVOTPURS III-Test-II-Test-X-I-Test 03-14-07 KDE")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	if got, want := settings.String(), "Test-KD Test=AQBGCKDIELFXHZMWNVOTPURS III-Test-II-Test-X-I-Test 03-14-07 KDE"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+	e, err := NewEnigmaFromSettings(settings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	text := strings.Repeat("REGISTEREDMODEL", 20)
+	encoded, err := e.Encode(text)
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+	e.RotorsReset()
+	if decoded, _ := e.Encode(encoded); decoded != text {
+		t.Errorf("want = %v\n got = %v", text, decoded)
+	}
+}
+
+func TestRegister_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func() error
+	}{
+		{"rotor exists", func() error { return RegisterRotor(RotorI, RotorDefinition{Wiring: etwAbcdef}) }},
+		{"rotor empty name", func() error { return RegisterRotor("", RotorDefinition{Wiring: etwAbcdef}) }},
+		{"rotor name with space", func() error { return RegisterRotor("I X", RotorDefinition{Wiring: etwAbcdef}) }},
+		{"rotor incomplete wiring", func() error { return RegisterRotor("I-Err", RotorDefinition{Wiring: "ABC"}) }},
+		{"rotor invalid notch", func() error {
+			return RegisterRotor("I-Err", RotorDefinition{Wiring: etwAbcdef, NotchPositions: "a"})
+		}},
+		{"reflector exists", func() error {
+			return RegisterReflector(UkwB, ReflectorDefinition{Wiring: "YRUHQSLDPXNGOKMIEBFZCWVJAT"})
+		}},
+		{"reflector not paired", func() error { return RegisterReflector("Err", ReflectorDefinition{Wiring: etwQwertz}) }},
+		{"reflector letter to itself", func() error { return RegisterReflector("Err", ReflectorDefinition{Wiring: etwAbcdef}) }},
+		{"reflector invalid name", func() error {
+			return RegisterReflector("Err@", ReflectorDefinition{Wiring: "YRUHQSLDPXNGOKMIEBFZCWVJAT"})
+		}},
+		{"model exists", func() error {
+			return RegisterModel(M3, ModelDefinition{Reflectors: []ReflectorModel{UkwB}, Rotors: []RotorModel{RotorI, RotorII, RotorIII}})
+		}},
+		{"model without reflector", func() error {
+			return RegisterModel("Err", ModelDefinition{Rotors: []RotorModel{RotorI, RotorII, RotorIII}})
+		}},
+		{"model unknown reflector", func() error {
+			return RegisterModel("Err", ModelDefinition{Reflectors: []ReflectorModel{"Err"}, Rotors: []RotorModel{RotorI, RotorII, RotorIII}})
+		}},
+		{"model unknown rotor", func() error {
+			return RegisterModel("Err", ModelDefinition{Reflectors: []ReflectorModel{UkwB}, Rotors: []RotorModel{RotorI, RotorII, "Err"}})
+		}},
+		{"model not enough rotors", func() error {
+			return RegisterModel("Err", ModelDefinition{Reflectors: []ReflectorModel{UkwB}, Rotors: []RotorModel{RotorI, RotorII, RotorBeta}})
+		}},
+		{"model without thin rotor", func() error {
+			return RegisterModel("Err", ModelDefinition{Reflectors: []ReflectorModel{UkwBThin}, Rotors: []RotorModel{RotorI, RotorII, RotorIII}, HasFourthRotor: true})
+		}},
+		{"model invalid etw", func() error {
+			return RegisterModel("Err", ModelDefinition{Reflectors: []ReflectorModel{UkwB}, Rotors: []RotorModel{RotorI, RotorII, RotorIII}, EtwWiring: "AAB"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.register(); err == nil {
+				t.Errorf("expected error, got none")
+			}
+		})
+	}
+	if Model("Err").exists() {
+		t.Errorf("invalid model must not be registered")
+	}
+}
+
+func TestRegisterModel_Alphabet(t *testing.T) {
+	const digits = "0123456789"
+	rotors := map[RotorModel]string{"I-Toy": "7019384265", "II-Toy": "2946018573", "III-Toy": "5203876194"}
+	for model, wiring := range rotors {
+		if err := RegisterRotor(model, RotorDefinition{Wiring: wiring, NotchPositions: "9", Alphabet: digits}); err != nil {
+			t.Errorf("register rotor error = %v", err)
+			return
+		}
+	}
+	if err := RegisterReflector("Toy", ReflectorDefinition{Wiring: "5879604213", IsMovable: true, Alphabet: digits}); err != nil {
+		t.Errorf("register reflector error = %v", err)
+		return
+	}
+	err := RegisterModel("Toy", ModelDefinition{
+		HasPlugboard: true,
+		Reflectors:   []ReflectorModel{"Toy"},
+		Rotors:       []RotorModel{"I-Toy", "II-Toy", "III-Toy"},
+		Alphabet:     digits,
+	})
+	if err != nil {
+		t.Errorf("register model error = %v", err)
+		return
+	}
+	if got := Model("Toy").GetAlphabet(); got != digits {
+		t.Errorf("want = %v\n got = %v", digits, got)
+	}
+	if err = Model("Toy").RequireBasicAlphabet(); !errors.Is(err, ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", ErrUnsupportedAlphabet, err)
+	}
+	if err = M3.RequireBasicAlphabet(); err != nil {
+		t.Errorf("want = %v\n got = %v", nil, err)
+	}
+
+	settings, err := ParseSettings("Toy Toy@4 III-Toy-I-Toy-II-Toy 02-10-07 381 05 17")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	e, err := NewEnigmaFromSettings(settings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	if got, want := e.Settings().String(), settings.String(); got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+	text := strings.Repeat("0123456789", 30)
+	encoded, err := e.Encode(text)
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+	for i := range text {
+		if encoded[i] == text[i] {
+			t.Errorf("digit %s encoded to itself at position %d", string(text[i]), i)
+		}
+	}
+	e.RotorsReset()
+	if decoded, _ := e.Encode(encoded); decoded != text {
+		t.Errorf("want = %v\n got = %v", text, decoded)
+	}
+
+	// letters of the basic alphabet are not on the keyboard
+	if _, err = e.Encode("A"); err == nil {
+		t.Errorf("expected encode error, got none")
+	}
+	if err = e.RotorSetRing(Right, 11); err == nil {
+		t.Errorf("expected ring error, got none")
+	}
+	if err = e.RotorSetWheel(Right, 'A'); err == nil {
+		t.Errorf("expected wheel error, got none")
+	}
+	if err = RegisterModel("Toy-Mixed", ModelDefinition{Reflectors: []ReflectorModel{"Toy"}, Rotors: []RotorModel{RotorI, RotorII, RotorIII}, Alphabet: digits}); err == nil {
+		t.Errorf("expected alphabet mismatch error, got none")
+	}
+	if err = RegisterRotor("I-Err", RotorDefinition{Wiring: "1123", Alphabet: "0112"}); err == nil {
+		t.Errorf("expected invalid alphabet error, got none")
+	}
+	if err = RegisterReflector("Err", ReflectorDefinition{Wiring: "5879604213", IsRewirable: true, Alphabet: digits}); err == nil {
+		t.Errorf("expected rewirable reflector error, got none")
+	}
+}
@@ -0,0 +1,163 @@
+// Package rejewski implements the Polish method of breaking the Enigma traffic with doubled indicators (used until 1940).
+//
+// Message keys were enciphered twice at the day's ground setting, so the first and the fourth indicator letter
+// are encryptions of the same plaintext letter. A day's indicators therefore define the AD, BE and CF permutations.
+// Their cycle structure (the "characteristic") does not depend on the plugboard, so it can be looked up in a catalog
+// of all the rotor orders and start positions, built on Enigma with an empty plugboard.
+package rejewski
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+)
+
+const alphabetSize = enigma.AlphabetSize
+
+// Config specifies the machine and the rotor orders for building the catalog
+type Config struct {
+	Model       enigma.Model
+	Reflector   enigma.ReflectorModel                    // default reflector of the model if empty
+	RotorOrders []map[enigma.RotorSlot]enigma.RotorModel // all rotor orders supported by the model if empty
+	Workers     int                                      // number of CPUs if empty
+}
+
+// Catalog maps the characteristics to the rotor orders and start positions producing them
+type Catalog struct {
+	entries map[string][]enigma.Settings
+}
+
+// NewCatalog computes the characteristics of all the configured rotor orders and start positions (all rings on the first position)
+func NewCatalog(config Config) (Catalog, error) {
+	if err := config.Model.RequireBasicAlphabet(); err != nil {
+		return Catalog{}, err
+	}
+	if config.Reflector == "" {
+		e, err := enigma.NewEnigma(config.Model)
+		if err != nil {
+			return Catalog{}, err
+		}
+		config.Reflector = e.GetReflectorModel()
+	}
+	if len(config.RotorOrders) == 0 {
+		config.RotorOrders = config.Model.GetRotorOrders()
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	for _, order := range config.RotorOrders {
+		if _, err := newMachine(config, order); err != nil {
+			return Catalog{}, err
+		}
+	}
+
+	results := make([]map[string][]enigma.Settings, len(config.RotorOrders))
+	toolkit.RunParallel(config.Workers, len(results), func(i int) {
+		results[i] = catalogRotorOrder(config, config.RotorOrders[i])
+	})
+
+	catalog := Catalog{entries: map[string][]enigma.Settings{}}
+	for _, result := range results {
+		for characteristic, settings := range result {
+			catalog.entries[characteristic] = append(catalog.entries[characteristic], settings...)
+		}
+	}
+	return catalog, nil
+}
+
+// Lookup returns the rotor orders and start positions (as settings without the plugboard) matching the characteristic
+func (c Catalog) Lookup(characteristic Characteristic) []enigma.Settings {
+	return c.entries[characteristic.String()]
+}
+
+// Size returns the number of distinct characteristics in the catalog
+func (c Catalog) Size() int {
+	return len(c.entries)
+}
+
+func catalogRotorOrder(config Config, order map[enigma.RotorSlot]enigma.RotorModel) map[string][]enigma.Settings {
+	e, err := newMachine(config, order)
+	if err != nil {
+		panic(fmt.Errorf("failed to create Enigma: %w", err)) // should not happen, all the rotor orders are validated by NewCatalog
+	}
+	result := map[string][]enigma.Settings{}
+	slots := config.Model.GetAvailableRotorSlots()
+	positions := make([]byte, len(slots))
+	count := 1
+	for range slots {
+		count *= alphabetSize
+	}
+	for n := 0; n < count; n++ {
+		for i, rest := 0, n; i < len(slots); i, rest = i+1, rest/alphabetSize {
+			positions[i] = byte('A' + rest%alphabetSize)
+			if err = e.RotorSetWheel(slots[i], positions[i]); err != nil {
+				panic(fmt.Errorf("failed to set wheel position: %w", err))
+			}
+		}
+		characteristic, err := GetCharacteristic(&e)
+		if err != nil {
+			panic(fmt.Errorf("failed to compute characteristic: %w", err))
+		}
+		key := characteristic.String()
+		result[key] = append(result[key], e.Settings())
+	}
+	return result
+}
+
+func newMachine(config Config, order map[enigma.RotorSlot]enigma.RotorModel) (enigma.Enigma, error) {
+	rotors := make(map[enigma.RotorSlot]enigma.RotorConfig, len(order))
+	for slot, model := range order {
+		rotors[slot] = enigma.RotorConfig{Model: model}
+	}
+	e, err := enigma.NewEnigmaWithSetup(config.Model, rotors, enigma.ReflectorConfig{Model: config.Reflector}, "")
+	if err != nil {
+		return enigma.Enigma{}, fmt.Errorf("invalid catalog configuration: %w", err)
+	}
+	return e, nil
+}
+
+// GetCharacteristic computes the characteristic of the given machine at its current position
+// (the position the doubled message keys would be enciphered at). The machine position is left unchanged
+func GetCharacteristic(e *enigma.Enigma) (Characteristic, error) {
+	ad, be, cf, err := GetPermutations(e)
+	if err != nil {
+		return Characteristic{}, err
+	}
+	return NewCharacteristic(ad, be, cf)
+}
+
+// GetPermutations computes the AD, BE and CF permutations of the given machine at its current position.
+// The machine position is left unchanged
+func GetPermutations(e *enigma.Enigma) (ad, be, cf Permutation, err error) {
+	if err = e.Model.RequireBasicAlphabet(); err != nil {
+		return ad, be, cf, err
+	}
+	start := e.State()
+	defer func() {
+		if restoreErr := e.Restore(start); restoreErr != nil && err == nil {
+			err = restoreErr
+		}
+	}()
+
+	// permutations of the six consecutive machine positions
+	var scramblers [6]Permutation
+	for i := range scramblers {
+		scramblers[i] = newPermutation()
+		state := e.State()
+		for letter := 0; letter < alphabetSize; letter++ {
+			if err = e.Restore(state); err != nil {
+				return ad, be, cf, err
+			}
+			encoded, err := e.Encode(string(rune('A' + letter)))
+			if err != nil {
+				return ad, be, cf, err
+			}
+			scramblers[i][letter] = int(encoded[0] - 'A')
+		}
+	}
+
+	// the same letter enciphered at positions 1 and 4 gives AD (encryption is an involution, so A followed by D)
+	return scramblers[0].compose(scramblers[3]), scramblers[1].compose(scramblers[4]), scramblers[2].compose(scramblers[5]), nil
+}
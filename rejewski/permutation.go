@@ -0,0 +1,159 @@
+package rejewski
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Permutation maps every letter (by its index in the alphabet) to another one, unknown mappings are -1
+type Permutation [alphabetSize]int
+
+func newPermutation() Permutation {
+	p := Permutation{}
+	for i := range p {
+		p[i] = -1
+	}
+	return p
+}
+
+// IsComplete shows if the mapping is known for all the letters
+func (p Permutation) IsComplete() bool {
+	for _, mapped := range p {
+		if mapped == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Cycles returns the disjoint cycles of the permutation as letter strings, longest cycles first
+func (p Permutation) Cycles() ([]string, error) {
+	if !p.IsComplete() {
+		return nil, fmt.Errorf("incomplete permutation, not enough indicators")
+	}
+	var cycles []string
+	visited := [alphabetSize]bool{}
+	for start := 0; start < alphabetSize; start++ {
+		if visited[start] {
+			continue
+		}
+		var cycle []byte
+		for letter := start; !visited[letter]; letter = p[letter] {
+			visited[letter] = true
+			cycle = append(cycle, byte('A'+letter))
+		}
+		cycles = append(cycles, string(cycle))
+	}
+	sort.SliceStable(cycles, func(i, j int) bool {
+		return len(cycles[i]) > len(cycles[j])
+	})
+	return cycles, nil
+}
+
+// String returns the permutation in the cycle notation, ie. "(AB)(C)...", unknown mappings are marked by "?"
+func (p Permutation) String() string {
+	cycles, err := p.Cycles()
+	if err != nil {
+		var builder strings.Builder
+		for i, mapped := range p {
+			if mapped == -1 {
+				builder.WriteString(string(rune('A'+i)) + "?")
+			} else {
+				builder.WriteString(string(rune('A'+i)) + string(rune('A'+mapped)))
+			}
+			builder.WriteByte(' ')
+		}
+		return strings.TrimSpace(builder.String())
+	}
+	return "(" + strings.Join(cycles, ")(") + ")"
+}
+
+// CycleStructure returns the lengths of the permutation cycles, longest first
+func (p Permutation) CycleStructure() ([]int, error) {
+	cycles, err := p.Cycles()
+	if err != nil {
+		return nil, err
+	}
+	lengths := make([]int, len(cycles))
+	for i, cycle := range cycles {
+		lengths[i] = len(cycle)
+	}
+	return lengths, nil
+}
+
+// compose returns the permutation applying first p and then the other permutation
+func (p Permutation) compose(other Permutation) Permutation {
+	result := newPermutation()
+	for i, mapped := range p {
+		if mapped != -1 {
+			result[i] = other[mapped]
+		}
+	}
+	return result
+}
+
+// Characteristic is the cycle structure of the AD, BE and CF permutations,
+// it does not depend on the plugboard settings and only identifies the rotor order and the start position
+type Characteristic struct {
+	AD []int
+	BE []int
+	CF []int
+}
+
+// String returns the characteristic in the compact form, ie. "13 13 | 10 10 2 2 1 1 | 9 9 4 4"
+func (c Characteristic) String() string {
+	parts := make([]string, 3)
+	for i, lengths := range [][]int{c.AD, c.BE, c.CF} {
+		numbers := make([]string, len(lengths))
+		for j, length := range lengths {
+			numbers[j] = strconv.Itoa(length)
+		}
+		parts[i] = strings.Join(numbers, " ")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// NewCharacteristic computes the characteristic from the AD, BE and CF permutations
+func NewCharacteristic(ad, be, cf Permutation) (Characteristic, error) {
+	result := Characteristic{}
+	for _, item := range []struct {
+		permutation Permutation
+		target      *[]int
+	}{{ad, &result.AD}, {be, &result.BE}, {cf, &result.CF}} {
+		lengths, err := item.permutation.CycleStructure()
+		if err != nil {
+			return Characteristic{}, err
+		}
+		*item.target = lengths
+	}
+	return result, nil
+}
+
+// Permutations builds the AD, BE and CF permutations from the day's doubled indicators (message keys enciphered twice,
+// ie. "DMQVBN"). Mapping of a letter is only known if some indicator starts with it, so the permutations are complete
+// only if there are enough indicators (usually about 80 are needed)
+func Permutations(indicators []string) (ad, be, cf Permutation, err error) {
+	permutations := [3]Permutation{newPermutation(), newPermutation(), newPermutation()}
+	inverse := [3]Permutation{newPermutation(), newPermutation(), newPermutation()}
+	for _, indicator := range indicators {
+		indicator = strings.ReplaceAll(indicator, " ", "")
+		if len(indicator) != 6 {
+			return ad, be, cf, fmt.Errorf("invalid indicator \"%s\", must be 6 letters long", indicator)
+		}
+		for i := 0; i < 3; i++ {
+			from, to := indicator[i], indicator[i+3]
+			if from < 'A' || from > 'Z' || to < 'A' || to > 'Z' {
+				return ad, be, cf, fmt.Errorf("invalid indicator \"%s\", only uppercase letters A-Z allowed", indicator)
+			}
+			mapped, mappedFrom := permutations[i][from-'A'], inverse[i][to-'A']
+			if (mapped != -1 && mapped != int(to-'A')) || (mappedFrom != -1 && mappedFrom != int(from-'A')) {
+				return ad, be, cf, fmt.Errorf("indicator \"%s\" contradicts the other indicators, was it enciphered with a different key?", indicator)
+			}
+			permutations[i][from-'A'] = int(to - 'A')
+			inverse[i][to-'A'] = int(from - 'A')
+		}
+	}
+	return permutations[0], permutations[1], permutations[2], nil
+}
@@ -0,0 +1,136 @@
+package rejewski
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestPermutations(t *testing.T) {
+	ad, be, cf, err := Permutations([]string{"ABCBCA", "BCACAB", "CAB ABC"})
+	if err != nil {
+		t.Errorf("permutations error = %v", err)
+		return
+	}
+	if ad[0] != 1 || ad[1] != 2 || ad[2] != 0 || ad[3] != -1 {
+		t.Errorf("invalid AD permutation %v", ad)
+	}
+	if be.IsComplete() || cf.IsComplete() {
+		t.Errorf("permutations should not be complete")
+	}
+	if _, err = be.Cycles(); err == nil {
+		t.Errorf("expected incomplete permutation error, got none")
+	}
+
+	invalid := [][]string{
+		{"ABCDE"},
+		{"ABCDEf"},
+		{"ABCDEF", "AXXEXX"}, // A maps to both D and E
+		{"ABCDEF", "XXXDXX"}, // both A and X map to D
+	}
+	for _, indicators := range invalid {
+		if _, _, _, err = Permutations(indicators); err == nil {
+			t.Errorf("expected error for indicators %v, got none", indicators)
+		}
+	}
+}
+
+func TestPermutation_Cycles(t *testing.T) {
+	p := Permutation{}
+	for i := range p {
+		p[i] = i
+	}
+	p[0], p[1], p[2] = 1, 2, 0 // (ABC)
+	p[3], p[4] = 4, 3          // (DE)
+	if got, want := p.String(), "(ABC)(DE)(F)(G)(H)(I)(J)(K)(L)(M)(N)(O)(P)(Q)(R)(S)(T)(U)(V)(W)(X)(Y)(Z)"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+	lengths, _ := p.CycleStructure()
+	if lengths[0] != 3 || lengths[1] != 2 || len(lengths) != 23 {
+		t.Errorf("invalid cycle structure %v", lengths)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	// traffic of a single day - random message keys enciphered twice at the day's ground setting
+	daySettings, err := enigma.ParseSettings("I A II-I-III 01-01-01 KDR AB CD EF GH IJ KL")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	e, err := enigma.NewEnigmaFromSettings(daySettings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	random := rand.New(rand.NewSource(1))
+	indicators := make([]string, 150)
+	for i := range indicators {
+		key := make([]byte, 3)
+		for j := range key {
+			key[j] = byte('A' + random.Intn(26))
+		}
+		e.RotorsReset()
+		if indicators[i], err = e.Encode(strings.Repeat(string(key), 2)); err != nil {
+			t.Errorf("encode error = %v", err)
+			return
+		}
+	}
+
+	ad, be, cf, err := Permutations(indicators)
+	if err != nil {
+		t.Errorf("permutations error = %v", err)
+		return
+	}
+	characteristic, err := NewCharacteristic(ad, be, cf)
+	if err != nil {
+		t.Errorf("characteristic error = %v", err)
+		return
+	}
+
+	// cycles always come in pairs of the same length
+	for _, lengths := range [][]int{characteristic.AD, characteristic.BE, characteristic.CF} {
+		for i := 0; i < len(lengths); i += 2 {
+			if lengths[i] != lengths[i+1] {
+				t.Errorf("cycles not paired in characteristic %s", characteristic)
+			}
+		}
+	}
+
+	catalog, err := NewCatalog(Config{
+		Model:       enigma.One,
+		Reflector:   enigma.UkwA,
+		RotorOrders: []map[enigma.RotorSlot]enigma.RotorModel{{enigma.Left: enigma.RotorII, enigma.Middle: enigma.RotorI, enigma.Right: enigma.RotorIII}},
+	})
+	if err != nil {
+		t.Errorf("catalog error = %v", err)
+		return
+	}
+	found := false
+	for _, settings := range catalog.Lookup(characteristic) {
+		if settings.String() == "I A II-I-III 01-01-01 KDR" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("day's ground setting not found for characteristic %s, catalog entries: %v", characteristic, catalog.Lookup(characteristic))
+	}
+}
+
+func TestCatalog_Alphabet(t *testing.T) {
+	if _, err := NewCatalog(Config{Model: enigmatest.RegisterNumeric()}); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+	e, err := enigma.NewEnigma(enigmatest.RegisterNumeric())
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+	if _, err = GetCharacteristic(&e); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+}
@@ -13,6 +13,7 @@ type RotorConfig = struct {
 
 type rotor struct {
 	model                RotorModel
+	alphabet             *alphabet
 	wiringMapIn          map[int]int // In = first pass through the rotors (from the plugboard to the reflector)
 	wiringMapOut         map[int]int // Out = second pass (from the reflector to the plugboard)
 	notchPositions       []int
@@ -21,27 +22,27 @@ type rotor struct {
 	ringPosition         int
 }
 
-func newRotor(rotorModel RotorModel) rotor {
+func newRotor(rotorModel RotorModel, a *alphabet) rotor {
 	if !rotorModel.exists() {
 		panic(fmt.Errorf("unsupported rotor model"))
 	}
 	wiring := rotorModel.getWiring()
-	if !Alphabet.isValidWiring(wiring) {
+	if !a.isValidWiring(wiring) {
 		panic(fmt.Errorf("invalid rotor wiring %s", wiring))
 	}
-	notchPositions := make([]int, len(rotorModel.getNotchPositions()))
-	for i, notchPositionByte := range rotorModel.getNotchPositions() {
-		notchPositionInt, ok := Alphabet.charToInt(notchPositionByte)
+	notchPositions := make([]int, len(rotorModel.GetNotchPositions()))
+	for i, notchPositionByte := range rotorModel.GetNotchPositions() {
+		notchPositionInt, ok := a.charToInt(notchPositionByte)
 		if !ok {
 			panic(fmt.Errorf("invalid notch position %s", string(notchPositionByte)))
 		}
 		notchPositions[i] = notchPositionInt
 	}
 
-	in := make(map[int]int, Alphabet.getSize())
-	out := make(map[int]int, Alphabet.getSize())
+	in := make(map[int]int, a.getSize())
+	out := make(map[int]int, a.getSize())
 	for i, letter := range wiring {
-		letterIndex, ok := Alphabet.charToInt(byte(letter))
+		letterIndex, ok := a.charToInt(byte(letter))
 		if !ok {
 			panic(fmt.Errorf("unsupported wiring letter %s", string(letter))) // should not happen, we already checked the wiring validity
 		}
@@ -51,17 +52,26 @@ func newRotor(rotorModel RotorModel) rotor {
 
 	return rotor{
 		model:                rotorModel,
+		alphabet:             a,
 		wiringMapIn:          in,
 		wiringMapOut:         out,
 		notchPositions:       notchPositions,
-		initialWheelPosition: Alphabet.intToChar(0),
+		initialWheelPosition: a.intToChar(0),
 		wheelPosition:        0, // start on the first position by default
 		ringPosition:         1,
 	}
 }
 
+func (r *rotor) clone() rotor {
+	result := *r
+	result.wiringMapIn = copyLetterMap(r.wiringMapIn)
+	result.wiringMapOut = copyLetterMap(r.wiringMapOut)
+	result.notchPositions = append([]int(nil), r.notchPositions...)
+	return result
+}
+
 func (r *rotor) setWheelPosition(letter byte) error {
-	index, ok := Alphabet.charToInt(letter)
+	index, ok := r.alphabet.charToInt(letter)
 	if !ok {
 		return fmt.Errorf("unsupported rotor wheel position \"%s\"", string(letter))
 	}
@@ -74,14 +84,22 @@ func (r *rotor) getWheelPosition() int {
 	return r.wheelPosition
 }
 
+func (r *rotor) getInitialWheelPosition() byte {
+	return r.initialWheelPosition
+}
+
 func (r *rotor) setRingPosition(position int) error {
-	if position < 1 || position > Alphabet.getSize() {
-		return fmt.Errorf("invalid ring position %d, must be a number between 1 and %d", position, Alphabet.getSize())
+	if position < 1 || position > r.alphabet.getSize() {
+		return fmt.Errorf("invalid ring position %d, must be a number between 1 and %d", position, r.alphabet.getSize())
 	}
 	r.ringPosition = position
 	return nil
 }
 
+func (r *rotor) getRingPosition() int {
+	return r.ringPosition
+}
+
 func (r *rotor) reset() {
 	if err := r.setWheelPosition(r.initialWheelPosition); err != nil {
 		panic(fmt.Errorf("failed to reset rotor %s: %w", r.model, err))
@@ -98,19 +116,19 @@ func (r *rotor) translateOut(input int) int {
 
 func (r *rotor) translate(input int, translateMap map[int]int) int {
 	shiftSize := r.wheelPosition - r.ringPosition + 1
-	rotatedInput := shift(input, shiftSize)     // shift according to the wheel and ring rotation
-	rotatedOutput := translateMap[rotatedInput] // translate
-	return shift(rotatedOutput, -shiftSize)     // shift back
+	rotatedInput := r.alphabet.shift(input, shiftSize) // shift according to the wheel and ring rotation
+	rotatedOutput := translateMap[rotatedInput]        // translate
+	return r.alphabet.shift(rotatedOutput, -shiftSize) // shift back
 }
 
 func (r *rotor) rotate() {
-	r.wheelPosition = (r.wheelPosition + 1) % Alphabet.getSize()
+	r.wheelPosition = (r.wheelPosition + 1) % r.alphabet.getSize()
 }
 
 func (r *rotor) shouldRotateNext() bool {
 	for _, notchPosition := range r.notchPositions {
 		if r.wheelPosition == notchPosition {
-			return true // double-stepping - we are about to cross a notch in the next step, next rotor should be rotated too then
+			return true // we are about to cross a notch in the next step, next rotor should be rotated too then
 		}
 	}
 	return false
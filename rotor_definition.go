@@ -33,31 +33,65 @@ const (
 	RotorVIT   RotorModel = "VI-T"
 	RotorVIIT  RotorModel = "VII-T"
 	RotorVIIIT RotorModel = "VIII-T"
+
+	RotorIG31   RotorModel = "I-G31"
+	RotorIIG31  RotorModel = "II-G31"
+	RotorIIIG31 RotorModel = "III-G31"
+
+	RotorIG312   RotorModel = "I-G312"
+	RotorIIG312  RotorModel = "II-G312"
+	RotorIIIG312 RotorModel = "III-G312"
+
+	RotorIG260   RotorModel = "I-G260"
+	RotorIIG260  RotorModel = "II-G260"
+	RotorIIIG260 RotorModel = "III-G260"
+
+	RotorIR   RotorModel = "I-R"
+	RotorIIR  RotorModel = "II-R"
+	RotorIIIR RotorModel = "III-R"
+
+	RotorIN   RotorModel = "I-N"
+	RotorIIN  RotorModel = "II-N"
+	RotorIIIN RotorModel = "III-N"
+	RotorIVN  RotorModel = "IV-N"
+	RotorVN   RotorModel = "V-N"
 )
 
 func (r RotorModel) exists() bool {
-	_, ok := rotorDefinitions[r]
+	_, ok := getRotorDefinition(r)
 	return ok
 }
 
-func (r RotorModel) getNotchPositions() []byte {
-	return rotorDefinitions[r].notchPositions
+// GetNotchPositions returns the wheel positions (letters in the window) from which the rotor steps the next rotor
+func (r RotorModel) GetNotchPositions() []byte {
+	definition, _ := getRotorDefinition(r)
+	return append([]byte(nil), definition.notchPositions...)
 }
 
 // IsThin determines if this rotor model is thin or normal size,
 // only thin rotors can be placed into the last slot of 4-rotor Enigma models
 func (r RotorModel) IsThin() bool {
-	return rotorDefinitions[r].isThin
+	definition, _ := getRotorDefinition(r)
+	return definition.isThin
 }
 
 func (r RotorModel) getWiring() string {
-	return rotorDefinitions[r].wiring
+	definition, _ := getRotorDefinition(r)
+	return definition.wiring
 }
 
 type rotorDefinition struct {
 	notchPositions []byte
 	isThin         bool
 	wiring         string
+	alphabet       *alphabet // basic Alphabet if empty
+}
+
+func (d rotorDefinition) getAlphabet() *alphabet {
+	if d.alphabet != nil {
+		return d.alphabet
+	}
+	return &Alphabet
 }
 
 var rotorDefinitions = map[RotorModel]rotorDefinition{
@@ -185,4 +219,89 @@ var rotorDefinitions = map[RotorModel]rotorDefinition{
 		isThin:         false,
 		wiring:         "YMTPNZHWKODAJXELUQVGCBISFR",
 	},
+	RotorIG31: {
+		notchPositions: []byte("SUVWZABCEFGIKLOPQ"),
+		isThin:         false,
+		wiring:         "LPGSZMHAEOQKVXRFYBUTNICJDW",
+	},
+	RotorIIG31: {
+		notchPositions: []byte("STVYZACDFGHKMNQ"),
+		isThin:         false,
+		wiring:         "SLVGBTFXJQOHEWIRZYAMKPCNDU",
+	},
+	RotorIIIG31: {
+		notchPositions: []byte("UWXAEFHKMNR"),
+		isThin:         false,
+		wiring:         "CJGDPSHKTURAWZXFMYNQOBVLIE",
+	},
+	RotorIG312: {
+		notchPositions: []byte("SUVWZABCEFGIKLOPQ"),
+		isThin:         false,
+		wiring:         "DMTWSILRUYQNKFEJCAZBPGXOHV",
+	},
+	RotorIIG312: {
+		notchPositions: []byte("STVYZACDFGHKMNQ"),
+		isThin:         false,
+		wiring:         "HQZGPJTMOBLNCIFDYAWVEUSRKX",
+	},
+	RotorIIIG312: {
+		notchPositions: []byte("UWXAEFHKMNR"),
+		isThin:         false,
+		wiring:         "UQNTLSZFMREHDPXKIBVYGJCWOA",
+	},
+	RotorIG260: {
+		notchPositions: []byte("SUVWZABCEFGIKLOPQ"),
+		isThin:         false,
+		wiring:         "RCSPBLKQAUMHWYTIFZVGOJNEXD",
+	},
+	RotorIIG260: {
+		notchPositions: []byte("STVYZACDFGHKMNQ"),
+		isThin:         false,
+		wiring:         "WCMIBVPJXAROSGNDLZKEYHUFQT",
+	},
+	RotorIIIG260: {
+		notchPositions: []byte("UWXAEFHKMNR"),
+		isThin:         false,
+		wiring:         "FVDHZELSQMAXOKYIWPGCBUJTNR",
+	},
+	RotorIR: {
+		notchPositions: []byte{'N'},
+		isThin:         false,
+		wiring:         "JGDQOXUSCAMIFRVTPNEWKBLZYH",
+	},
+	RotorIIR: {
+		notchPositions: []byte{'E'},
+		isThin:         false,
+		wiring:         "NTZPSFBOKMWRCJDIVLAEYUXHGQ",
+	},
+	RotorIIIR: {
+		notchPositions: []byte{'Y'},
+		isThin:         false,
+		wiring:         "JVIUBHTCDYAKEQZPOSGXNRMWFL",
+	},
+	RotorIN: {
+		notchPositions: []byte{'Q'},
+		isThin:         false,
+		wiring:         "WTOKASUYVRBXJHQCPZEFMDINLG",
+	},
+	RotorIIN: {
+		notchPositions: []byte{'E'},
+		isThin:         false,
+		wiring:         "GJLPUBSWEMCTQVHXAOFZDRKYNI",
+	},
+	RotorIIIN: {
+		notchPositions: []byte{'V'},
+		isThin:         false,
+		wiring:         "JWFMHNBPUSDYTIXVZGRQLAOEKC",
+	},
+	RotorIVN: {
+		notchPositions: []byte{'J'},
+		isThin:         false,
+		wiring:         "ESOVPZJAYQUIRHXLNFTGKDCMWB",
+	},
+	RotorVN: {
+		notchPositions: []byte{'Z'},
+		isThin:         false,
+		wiring:         "HEJXQOTZBVFDASCILWPGYNMURK",
+	},
 }
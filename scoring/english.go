@@ -0,0 +1,35 @@
+package scoring
+
+// englishSample is a sample of English text used to build the n-gram statistics for scoring the decrypts.
+// It is only a few thousand letters long, so the statistics are toy data, load the counts of a large corpus with
+// LoadNGrams for real use
+const englishSample = `
+The weather report for the coming night expects strong winds from the south west with heavy rain spreading over the whole region.
+Ships in the northern waters are advised to seek shelter in the nearest harbour until the storm has passed over the islands.
+The convoy left the port early this morning and is expected to reach its destination within the next eight days if all goes well.
+Escort vessels have been ordered to keep close contact with the merchant ships and to report any sighting of enemy submarines at once.
+The enemy has been observed moving troops and supplies towards the river during the last two nights under the cover of darkness.
+Our patrols have taken several prisoners who confirm that a large attack is being prepared for the end of the week.
+All units are to remain on the alert and to keep their positions until further orders are received from headquarters.
+The commander wishes to thank the officers and men for their courage and endurance during the difficult fighting of the past month.
+When the sun went down over the hills the village was quiet and only the sound of the distant guns could be heard from time to time.
+The old man sat by the fire and told the children stories about the sea and the ships he had sailed on when he was young.
+It was a long and cold winter, and many families in the town did not have enough food or coal to keep their houses warm.
+In the spring the fields were green again and the farmers went out early every morning to plough the land and plant the seeds.
+The railway line between the two cities was damaged by the floods and the trains could not run for almost three weeks.
+Engineers worked day and night to repair the bridges and the tracks, and the first train finally arrived on a bright Sunday morning.
+The meeting of the council will take place on Thursday at ten o clock in the great hall of the town house.
+Members are kindly asked to bring the reports of their committees and to send any proposals to the secretary before Monday.
+The letter arrived at the office late in the afternoon, and nobody knew who had sent it or what the strange numbers in it meant.
+After a long discussion the clerks decided to take it to the director, who read it twice and then locked it in his desk without a word.
+Science has made great progress in the last hundred years, and machines now do much of the work that was once done by hand.
+Yet there are still many questions that cannot be answered, and the study of nature remains as exciting as it has ever been.
+The library holds thousands of books on history, language and mathematics, and it is open to every student of the university.
+Visitors are requested to keep silence in the reading rooms and to return all books to the desk before they leave the building.
+She opened the window and looked out at the garden where the roses were in full bloom and the birds were singing in the trees.
+He said that he would come back in the evening, but the hours passed and there was still no sign of him on the road.
+The government has announced new measures to support industry and to provide work for the thousands of men who have lost their jobs.
+Critics argue that the plan is too expensive and that it will take many years before any real results can be seen.
+We must never forget that the freedom we enjoy today was won by the sacrifice of those who came before us.
+It is our duty to protect it and to hand it on to our children and to their children after them.
+`
@@ -0,0 +1,48 @@
+package scoring
+
+// germanSample is a sample of German text (in the Enigma-friendly form, umlauts replaced by vowel pairs)
+// used to build the n-gram statistics for scoring the decrypts. It is only a few thousand letters long, so the statistics
+// are toy data, load the counts of a large corpus with LoadNGrams for real use
+const germanSample = `
+Das Oberkommando der Wehrmacht gibt bekannt: An der gesamten Ostfront herrschte gestern nur geringe Kampftaetigkeit.
+Im mittleren Abschnitt wurden oertliche Angriffe des Feindes abgewiesen. Eigene Stosstrupps brachten Gefangene ein.
+Die Luftwaffe bekaempfte mit starken Kraeften Truppenansammlungen und Nachschubverbindungen hinter der feindlichen Front.
+In Nordafrika lebhafte Spaehtruppentaetigkeit. Deutsche Kampfflugzeuge griffen Hafenanlagen und Flugplaetze an der Kueste an.
+Im Atlantik versenkten Unterseeboote aus einem stark gesicherten Geleitzug vier Schiffe mit zusammen zwanzigtausend Bruttoregistertonnen.
+Ein weiteres Schiff wurde durch Torpedotreffer schwer beschaedigt. Die Boote setzen die Verfolgung des Geleitzuges fort.
+Wetterbericht fuer die Biskaya: Wind aus Suedwest, Staerke vier bis fuenf, zunehmend, Seegang drei, Sicht gut, spaeter Regen.
+Luftdruck fallend, Temperatur zwoelf Grad. Fuer die Nacht wird eine Verschlechterung der Wetterlage mit Sturmboeen erwartet.
+An alle Einheiten: Der Funkverkehr ist auf das Notwendigste zu beschraenken. Meldungen ueber Feindbewegungen sind sofort weiterzugeben.
+Die neuen Schluessel treten mit dem ersten des kommenden Monats in Kraft. Die alten Unterlagen sind nach Erhalt zu vernichten.
+Der Kommandant meldet: Boot hat Stellung erreicht, Brennstoff ausreichend fuer drei Wochen, keine besonderen Vorkommnisse.
+Feindlicher Zerstoerer in Sicht, Kurs Nord, Fahrt hoch. Boot taucht ab und setzt sich nach Westen ab. Erbitte neue Befehle.
+Der Befehlshaber der Unterseeboote befiehlt: Gruppe Wolf bildet ab morgen frueh einen Vorpostenstreifen im Quadrat nordwestlich der Azoren.
+Die Boote haben bei Sichtung des Geleitzuges sofort Fuehlung zu halten und laufend Standortmeldungen zu geben.
+Es ist nicht zu angreifen, bevor nicht mindestens drei Boote heran sind. Nach dem Angriff ist Bericht zu erstatten.
+Die Versorgung durch das Versorgungsboot erfolgt am zehnten im Quadrat suedlich der Inselgruppe. Treffpunkt und Zeit werden noch bekannt gegeben.
+Die Division hat den Auftrag, die Stellungen am Fluss zu halten und den Uebergang des Gegners unter allen Umstaenden zu verhindern.
+Das Regiment greift bei Tagesanbruch an und nimmt die Hoehe westlich des Dorfes. Artillerie unterstuetzt den Angriff mit Sperrfeuer.
+Die Panzer stehen bereit und folgen der Infanterie in zweiter Welle. Pioniere haben die Minenfelder vor der Front zu raeumen.
+Verluste des Tages: drei Gefallene, elf Verwundete, zwei Vermisste. Munition und Verpflegung sind bis morgen Abend nachzufuehren.
+Der Gegner verstaerkt seine Kraefte im Raum noerdlich der Stadt. Es wird mit einem Grossangriff in den naechsten Tagen gerechnet.
+Alle Verbaende haben sich auf die Abwehr vorzubereiten und die Verbindung untereinander staendig aufrecht zu erhalten.
+Die Flugzeuge des Geschwaders starten um sechs Uhr zum Einsatz gegen die feindlichen Flugplaetze. Jagdschutz durch zwei Staffeln.
+Nach der Rueckkehr ist der Einsatzbericht mit genauen Angaben ueber Ziele, Treffer und Abwehr vorzulegen.
+Am Abend wurde die Stadt von feindlichen Bombern angegriffen. Es entstanden Schaeden an Wohnhaeusern und an den Bahnanlagen.
+Die Feuerwehr und die Hilfsmannschaften waren die ganze Nacht im Einsatz. Der Verkehr auf der Strecke nach Osten ist unterbrochen.
+Der Zug mit Ersatzteilen fuer die Werkstatt ist gestern eingetroffen. Die Instandsetzung der beschaedigten Fahrzeuge wird fortgesetzt.
+Es wird gebeten, die Anforderungen fuer den naechsten Monat bis zum fuenfzehnten an die Abteilung zu senden.
+Die Ausbildung der neuen Rekruten beginnt am Montag. Die Lehrgaenge fuer Funker und Fernsprecher werden in der Kaserne abgehalten.
+Ein Offizier und vier Unteroffiziere werden zum Lehrgang an die Nachrichtenschule kommandiert. Abreise am Mittwoch mit dem Fruehzug.
+Der Urlaub ist bis auf weiteres gesperrt. Ausnahmen koennen nur vom Kommandeur persoenlich genehmigt werden.
+Die Post fuer die Truppe wird ab sofort ueber die Feldpoststelle im Hauptquartier geleitet. Die neuen Nummern sind beigefuegt.
+Wir haben heute den Befehl erhalten, morgen in aller Frueh aufzubrechen und in die neue Stellung am Waldrand zu verlegen.
+Das Wetter ist kalt und regnerisch, die Wege sind aufgeweicht, und die Fahrzeuge kommen nur langsam vorwaerts.
+In der Nacht hoerten wir den Donner der Geschuetze von der Front her. Niemand konnte schlafen, alle warteten auf den Morgen.
+Der Hauptmann sprach zu den Maennern und sagte, dass es eine schwere Aufgabe sei, aber dass er sich auf jeden einzelnen verlassen koenne.
+Die Sonne ging langsam unter, und ueber dem Meer lag ein leichter Nebel. Das Schiff lief mit kleiner Fahrt in den Hafen ein.
+Auf der Bruecke standen der Kapitaen und der erste Offizier und beobachteten die Einfahrt durch die schmale Rinne zwischen den Sandbaenken.
+Die Mannschaft hatte seit Wochen kein Land mehr gesehen und freute sich auf ein paar ruhige Tage an Land und auf Nachrichten von zu Hause.
+Es gibt keine Nachricht von dem vermissten Boot. Letzte Meldung vor vier Tagen aus dem Seegebiet westlich von Irland.
+Die Suche durch Flugzeuge blieb ohne Ergebnis. Das Boot ist als verloren anzusehen, wenn bis morgen keine Meldung eingeht.
+`
@@ -0,0 +1,76 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+)
+
+// Language contains the letter statistics of a natural language
+type Language struct {
+	Name      string
+	Monograms *NGrams // letter frequencies
+	Bigrams   *NGrams
+	Trigrams  *NGrams
+	Sample    string // text the statistics were built from, empty if not built from a sample
+}
+
+// NewLanguage builds the language statistics from the given sample text
+func NewLanguage(name, sample string) (Language, error) {
+	language := Language{Name: name, Sample: sample}
+	for n, target := range []**NGrams{&language.Monograms, &language.Bigrams, &language.Trigrams} {
+		ngrams, err := NewNGramsFromText(sample, n+1)
+		if err != nil {
+			return Language{}, fmt.Errorf("failed to build %s statistics: %w", name, err)
+		}
+		*target = ngrams
+	}
+	return language, nil
+}
+
+// ChiSquared computes the chi-squared statistic of the text letter counts against the letter frequencies of the language,
+// lower is closer to the language (infinite for the texts without any letters A-Z)
+func (l Language) ChiSquared(text string) float64 {
+	counts, total := countLetters(text)
+	if total == 0 {
+		return math.Inf(1)
+	}
+	result := 0.0
+	for letter, count := range counts {
+		expected := float64(total) * l.Monograms.probability(letter)
+		diff := float64(count) - expected
+		result += diff * diff / expected
+	}
+	return result
+}
+
+// Kappa returns the probability that two letters picked from different places of a text in the language are the same
+// (the expected index of coincidence of the plaintext)
+func (l Language) Kappa() float64 {
+	result := 0.0
+	for letter := 0; letter < alphabetSize; letter++ {
+		p := l.Monograms.probability(letter)
+		result += p * p
+	}
+	return result
+}
+
+// ChiSquaredScorer scores the texts by their (negated) chi-squared statistic against the letter frequencies of the language
+func (l Language) ChiSquaredScorer() Scorer {
+	return ScorerFunc(func(text string) float64 {
+		return -l.ChiSquared(text)
+	})
+}
+
+// built-in languages, toy statistics built from short samples (see the package documentation)
+var (
+	German  = mustNewLanguage("German", germanSample)
+	English = mustNewLanguage("English", englishSample)
+)
+
+func mustNewLanguage(name, sample string) Language {
+	language, err := NewLanguage(name, sample)
+	if err != nil {
+		panic(err)
+	}
+	return language
+}
@@ -0,0 +1,168 @@
+package scoring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxNGramLength is the longest supported n-gram, the statistics keep a value for every possible n-gram (26^n values)
+const MaxNGramLength = 5
+
+// NGrams contains the log-probabilities of all the letter n-grams of a language
+type NGrams struct {
+	n        int
+	logProbs []float64
+}
+
+// NewNGrams creates the n-gram statistics from the given counts (all the n-grams must be of the same length)
+func NewNGrams(counts map[string]float64) (*NGrams, error) {
+	n := 0
+	for ngram := range counts {
+		n = len(ngram)
+		break
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no n-grams given")
+	}
+	if n > MaxNGramLength {
+		return nil, fmt.Errorf("invalid n-gram length %d, at most %d is supported", n, MaxNGramLength)
+	}
+
+	indexCounts := make([]float64, ngramsSize(n))
+	total := 0.0
+	for ngram, count := range counts {
+		if len(ngram) != n {
+			return nil, fmt.Errorf("invalid n-gram %s, all n-grams must be of length %d", ngram, n)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("invalid count %f for n-gram %s", count, ngram)
+		}
+		index := 0
+		for i := 0; i < n; i++ {
+			letter, ok := toIndex(ngram[i])
+			if !ok {
+				return nil, fmt.Errorf("invalid n-gram %s, unsupported letter %s", ngram, string(ngram[i]))
+			}
+			index = index*alphabetSize + letter
+		}
+		indexCounts[index] += count
+		total += count
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("all n-gram counts are zero")
+	}
+
+	return newNGramsFromCounts(n, indexCounts, total), nil
+}
+
+// NewNGramsFromText builds the n-gram statistics of length n from the given sample text (non-letters are skipped)
+func NewNGramsFromText(sample string, n int) (*NGrams, error) {
+	if n < 1 || n > MaxNGramLength {
+		return nil, fmt.Errorf("invalid n-gram length %d, must be between 1 and %d", n, MaxNGramLength)
+	}
+	letters := make([]int, 0, len(sample))
+	for i := 0; i < len(sample); i++ {
+		if letter, ok := toIndex(sample[i]); ok {
+			letters = append(letters, letter)
+		}
+	}
+	if len(letters) < n {
+		return nil, fmt.Errorf("sample text too short for n-grams of length %d", n)
+	}
+
+	counts := make([]float64, ngramsSize(n))
+	total := 0.0
+	for i := 0; i+n <= len(letters); i++ {
+		index := 0
+		for _, letter := range letters[i : i+n] {
+			index = index*alphabetSize + letter
+		}
+		counts[index]++
+		total++
+	}
+	return newNGramsFromCounts(n, counts, total), nil
+}
+
+// LoadNGrams reads the n-gram counts from the reader. Each line contains an n-gram and its count separated by whitespace
+// (ie. "TION 13168375"), empty lines and lines starting with # are skipped
+func LoadNGrams(reader io.Reader) (*NGrams, error) {
+	counts := map[string]float64{}
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line %d \"%s\", must contain the n-gram and its count", lineNumber, line)
+		}
+		count, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid count on line %d: %w", lineNumber, err)
+		}
+		counts[strings.ToUpper(fields[0])] += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read n-grams: %w", err)
+	}
+	return NewNGrams(counts)
+}
+
+func newNGramsFromCounts(n int, counts []float64, total float64) *NGrams {
+	// unseen n-grams get a small (but non-zero) probability
+	logProbs := make([]float64, len(counts))
+	floor := math.Log10(0.01 / total)
+	for i, count := range counts {
+		if count == 0 {
+			logProbs[i] = floor
+		} else {
+			logProbs[i] = math.Log10(count / total)
+		}
+	}
+	return &NGrams{n: n, logProbs: logProbs}
+}
+
+// N returns the length of the n-grams
+func (g *NGrams) N() int {
+	return g.n
+}
+
+// Score returns the average n-gram log-probability of the text, higher is more likely a plaintext (implements Scorer)
+func (g *NGrams) Score(text string) float64 {
+	sum, count := 0.0, 0
+	index, length := 0, 0
+	modulo := ngramsSize(g.n)
+	for i := 0; i < len(text); i++ {
+		letter, ok := toIndex(text[i])
+		if !ok {
+			continue
+		}
+		index = (index*alphabetSize + letter) % modulo // rolling index of the last n letters
+		if length++; length >= g.n {
+			sum += g.logProbs[index]
+			count++
+		}
+	}
+	if count == 0 {
+		return math.Inf(-1)
+	}
+	return sum / float64(count)
+}
+
+// probability returns the probability of the given n-gram (by its index)
+func (g *NGrams) probability(index int) float64 {
+	return math.Pow(10, g.logProbs[index])
+}
+
+func ngramsSize(n int) int {
+	size := 1
+	for i := 0; i < n; i++ {
+		size *= alphabetSize
+	}
+	return size
+}
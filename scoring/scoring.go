@@ -0,0 +1,69 @@
+// Package scoring provides fitness functions for rating how close a (decrypted) text is to natural language.
+// These are the core of all the statistical attacks on Enigma and of the automatic detection of correct decrypts.
+//
+// Available are the index of coincidence (language independent), chi-squared statistic against the letter frequencies
+// and n-gram log-probabilities. German and English statistics are built in, statistics for other languages can be loaded
+// from n-gram count files (see LoadNGrams).
+//
+// The built-in statistics are toy data built from a few paragraphs of sample text - good enough for the tests and for
+// telling a plaintext from random letters in long messages, but too coarse for the hill-climbing of short or noisy
+// ciphertexts. For real use load the n-gram counts of a large corpus with LoadNGrams.
+//
+// All the functions work with the 26 uppercase letters A-Z of the basic Enigma alphabet, other characters are ignored
+// (texts of the models with a custom alphabet, ie. digits, are not scored at all).
+package scoring
+
+const alphabetSize = 26
+
+// Scorer rates the given text, higher score means the text is more likely to be a plaintext
+type Scorer interface {
+	Score(text string) float64
+}
+
+// ScorerFunc adapts an ordinary function to the Scorer interface
+type ScorerFunc func(text string) float64
+
+// Score calls the underlying function
+func (f ScorerFunc) Score(text string) float64 {
+	return f(text)
+}
+
+// IndexOfCoincidence computes the probability of two randomly selected letters of the text being the same.
+// It is about 0.076 for German plaintext, 0.066 for English plaintext and 0.038 for random text
+func IndexOfCoincidence(text string) float64 {
+	counts, total := countLetters(text)
+	if total < 2 {
+		return 0
+	}
+	sum := 0
+	for _, count := range counts {
+		sum += count * (count - 1)
+	}
+	return float64(sum) / float64(total*(total-1))
+}
+
+// IoCScorer scores the texts by their index of coincidence
+var IoCScorer = ScorerFunc(IndexOfCoincidence)
+
+func countLetters(text string) ([alphabetSize]int, int) {
+	counts := [alphabetSize]int{}
+	total := 0
+	for i := 0; i < len(text); i++ {
+		if letter, ok := toIndex(text[i]); ok {
+			counts[letter]++
+			total++
+		}
+	}
+	return counts, total
+}
+
+// toIndex converts the letter (either case) to its index in the alphabet
+func toIndex(letter byte) (int, bool) {
+	switch {
+	case letter >= 'A' && letter <= 'Z':
+		return int(letter - 'A'), true
+	case letter >= 'a' && letter <= 'z':
+		return int(letter - 'a'), true
+	}
+	return 0, false
+}
@@ -0,0 +1,110 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const (
+	germanText  = "DIEBOOTESINDANGEWIESENDENGELEITZUGBEISICHTUNGSOFORTZUMELDENUNDFUEHLUNGZUHALTEN"
+	englishText = "THESHIPSAREORDEREDTOREPORTTHECONVOYASSOONASITISSIGHTEDANDTOKEEPCONTACTWITHIT"
+	randomText  = "QXJZKVWPMBYGFHCLUDNTRIESAOQXZJVKWMPYBFGHLCUDNRTSIAEOXQJZVKPWMBYFGHCLDUTNRSIEAO"
+)
+
+func TestIndexOfCoincidence(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want float64
+	}{
+		{name: "all different", text: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", want: 0},
+		{name: "all same", text: "AAAA", want: 1},
+		{name: "ignores non-letters", text: "A A-A a", want: 1},
+		{name: "too short", text: "A", want: 0},
+		{name: "digits of the numeric models", text: "3141592653", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IndexOfCoincidence(tt.text); got != tt.want {
+				t.Errorf("want = %v, got = %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for _, language := range []Language{German, English} {
+		t.Run(language.Name, func(t *testing.T) {
+			plaintext := germanText
+			if language.Name == "English" {
+				plaintext = englishText
+			}
+
+			if language.ChiSquared(plaintext) >= language.ChiSquared(randomText) {
+				t.Errorf("chi-squared of plaintext not lower than random text")
+			}
+			for _, ngrams := range []*NGrams{language.Monograms, language.Bigrams, language.Trigrams} {
+				if ngrams.Score(plaintext) <= ngrams.Score(randomText) {
+					t.Errorf("%d-gram score of plaintext not higher than random text", ngrams.N())
+				}
+			}
+			if kappa := language.Kappa(); kappa < 0.06 || kappa > 0.08 {
+				t.Errorf("kappa %v out of the expected range", kappa)
+			}
+		})
+	}
+
+	// the language statistics must tell the languages apart
+	if German.Trigrams.Score(germanText) <= English.Trigrams.Score(germanText) {
+		t.Errorf("German text scored better as English")
+	}
+	if English.Trigrams.Score(englishText) <= German.Trigrams.Score(englishText) {
+		t.Errorf("English text scored better as German")
+	}
+}
+
+func TestNGrams_ScoreDigits(t *testing.T) {
+	// ciphertexts of the models with a custom alphabet are not scored, only A-Z letters are
+	if got := German.Trigrams.Score("3141592653"); !math.IsInf(got, -1) {
+		t.Errorf("want = %v, got = %v", math.Inf(-1), got)
+	}
+	if got := German.ChiSquared("3141592653"); !math.IsInf(got, 1) {
+		t.Errorf("want = %v, got = %v", math.Inf(1), got)
+	}
+}
+
+func TestLoadNGrams(t *testing.T) {
+	ngrams, err := LoadNGrams(strings.NewReader("# bigram counts\nTH 30\nhe 20\n\nIN 50\n"))
+	if err != nil {
+		t.Errorf("load error = %v", err)
+		return
+	}
+	if ngrams.N() != 2 {
+		t.Errorf("n want = %v, got = %v", 2, ngrams.N())
+	}
+	if got, want := ngrams.Score("IN"), math.Log10(0.5); math.Abs(got-want) > 1e-9 {
+		t.Errorf("score want = %v, got = %v", want, got)
+	}
+	if ngrams.Score("THE") <= ngrams.Score("QXZ") {
+		t.Errorf("known n-grams not scored higher than unknown")
+	}
+
+	invalid := []string{
+		"",
+		"TH 30\nTHE 20",
+		"TH thirty",
+		"T1 30",
+		"TH 30 40",
+		"TH -5",
+		"ABCDEF 1",
+	}
+	for _, data := range invalid {
+		if _, err = LoadNGrams(strings.NewReader(data)); err == nil {
+			t.Errorf("expected load error for %q, got none", data)
+		}
+	}
+	if _, err = NewNGramsFromText(strings.Repeat("ENIGMA", 10), MaxNGramLength+1); err == nil {
+		t.Errorf("expected n-gram length error, got none")
+	}
+}
@@ -0,0 +1,333 @@
+package enigma
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Settings contains the full configuration of an Enigma machine (model, rotors, reflector and plugboard).
+// Can be parsed from and formatted to the usual key-sheet shorthand, see ParseSettings
+type Settings struct {
+	Model     Model
+	Rotors    map[RotorSlot]RotorConfig
+	Reflector ReflectorConfig
+	Plugboard string
+}
+
+// NewEnigmaFromSettings creates new Enigma machine configured by the given settings
+func NewEnigmaFromSettings(settings Settings) (Enigma, error) {
+	return NewEnigmaWithSetup(settings.Model, settings.Rotors, settings.Reflector, settings.Plugboard)
+}
+
+// ParseSettings parses the machine settings from the key-sheet shorthand, for example "M3 B I-IV-III 16-26-08 AJL AT BG DV".
+// The parts are separated by spaces and go in this order:
+//   - model (see Model constants)
+//   - reflector model, optionally followed by "@" and the wheel position for movable reflectors ("K@Y")
+//     or by "=" and the letter pairs for rewirable reflectors ("D=AQBGCKDIELFXHZMWNVOTPURS")
+//   - rotor models separated by "-", from the leftmost to the rightmost rotor (fourth rotor first in 4-rotor models)
+//   - ring positions separated by "-" in the same order
+//   - wheel positions, one letter per rotor in the same order (optional)
+//   - plugboard letter pairs (optional)
+func ParseSettings(settingsString string) (Settings, error) {
+	parts := strings.Fields(settingsString)
+	if len(parts) < 4 {
+		return Settings{}, fmt.Errorf("invalid settings \"%s\", must contain at least the model, reflector, rotors and ring positions", settingsString)
+	}
+
+	settings := Settings{Model: Model(parts[0])}
+	if !settings.Model.exists() {
+		return Settings{}, fmt.Errorf("unsupported model %s", settings.Model)
+	}
+
+	reflector, err := parseReflectorSettings(parts[1])
+	if err != nil {
+		return Settings{}, err
+	}
+	settings.Reflector = reflector
+
+	// rotors are written from left to right, slots go from right to left
+	slots := settings.Model.GetAvailableRotorSlots()
+	rotorModels := splitRotorModels(parts[2])
+	if len(rotorModels) != len(slots) {
+		return Settings{}, fmt.Errorf("invalid rotors \"%s\", %s model has %d rotors", parts[2], settings.Model.GetName(), len(slots))
+	}
+	ringPositions := strings.Split(parts[3], "-")
+	if len(ringPositions) != len(slots) {
+		return Settings{}, fmt.Errorf("invalid ring positions \"%s\", %s model has %d rotors", parts[3], settings.Model.GetName(), len(slots))
+	}
+	wheelPositions := ""
+	plugboardIndex := 4
+	if len(parts) > 4 && len(parts[4]) == len(slots) {
+		wheelPositions = parts[4]
+		plugboardIndex = 5
+	}
+
+	settings.Rotors = make(map[RotorSlot]RotorConfig, len(slots))
+	for i, slot := range slots {
+		index := len(slots) - i - 1
+		ringPosition, err := strconv.Atoi(ringPositions[index])
+		if err != nil {
+			return Settings{}, fmt.Errorf("invalid ring position \"%s\", must be a number", ringPositions[index])
+		}
+		config := RotorConfig{
+			Model:        rotorModels[index],
+			RingPosition: ringPosition,
+		}
+		if wheelPositions != "" {
+			config.WheelPosition = wheelPositions[index]
+		}
+		settings.Rotors[slot] = config
+	}
+
+	settings.Plugboard = strings.Join(parts[plugboardIndex:], " ")
+
+	// validate the settings by creating the machine
+	if _, err = NewEnigmaFromSettings(settings); err != nil {
+		return Settings{}, fmt.Errorf("invalid settings \"%s\": %w", settingsString, err)
+	}
+
+	return settings, nil
+}
+
+func parseReflectorSettings(reflectorString string) (ReflectorConfig, error) {
+	config := ReflectorConfig{}
+	if i := strings.IndexByte(reflectorString, '='); i != -1 {
+		wiring := reflectorString[i+1:]
+		if len(wiring)%2 != 0 {
+			return ReflectorConfig{}, fmt.Errorf("invalid reflector wiring \"%s\", must be a sequence of letter pairs", wiring)
+		}
+		pairs := make([]string, 0, len(wiring)/2)
+		for j := 0; j < len(wiring); j += 2 {
+			pairs = append(pairs, wiring[j:j+2])
+		}
+		config.Wiring = strings.Join(pairs, " ")
+		reflectorString = reflectorString[:i]
+	}
+	if i := strings.IndexByte(reflectorString, '@'); i != -1 {
+		position := reflectorString[i+1:]
+		if len(position) != 1 {
+			return ReflectorConfig{}, fmt.Errorf("invalid reflector wheel position \"%s\", must be a single letter", position)
+		}
+		config.WheelPosition = position[0]
+		reflectorString = reflectorString[:i]
+	}
+	config.Model = ReflectorModel(reflectorString)
+	return config, nil
+}
+
+// splitRotorModels splits the "-" separated rotor models, keeping together the models with "-" in their name (ie. "III-K-I-K-II-K")
+func splitRotorModels(rotorsString string) []RotorModel {
+	parts := strings.Split(rotorsString, "-")
+	result := make([]RotorModel, 0, len(parts))
+	for i := 0; i < len(parts); {
+		// use the longest existing model name, single part if there is none
+		end := i + 1
+		for j := len(parts); j > i+1; j-- {
+			if RotorModel(strings.Join(parts[i:j], "-")).exists() {
+				end = j
+				break
+			}
+		}
+		result = append(result, RotorModel(strings.Join(parts[i:end], "-")))
+		i = end
+	}
+	return result
+}
+
+// String formats the settings to the key-sheet shorthand accepted by ParseSettings.
+// Parts not specified in the settings are filled with the defaults of the model, settings of an unsupported model
+// (including the empty Settings) are formatted to a placeholder that ParseSettings rejects
+func (s Settings) String() string {
+	if !s.Model.exists() {
+		return fmt.Sprintf("<unsupported model %q>", s.Model)
+	}
+	slots := s.Model.GetAvailableRotorSlots()
+	defaultRotors := s.Model.getDefaultRotorModels()
+	rotorModels := make([]string, len(slots))
+	ringPositions := make([]string, len(slots))
+	wheelPositions := make([]byte, len(slots))
+	for i, slot := range slots {
+		index := len(slots) - i - 1
+		config := s.Rotors[slot]
+		if config.Model == "" {
+			config.Model = defaultRotors[slot]
+		}
+		if config.RingPosition == 0 {
+			config.RingPosition = 1
+		}
+		if config.WheelPosition == 0 {
+			config.WheelPosition = s.Model.getAlphabet().intToChar(0)
+		}
+		rotorModels[index] = string(config.Model)
+		ringPositions[index] = fmt.Sprintf("%02d", config.RingPosition)
+		wheelPositions[index] = config.WheelPosition
+	}
+
+	reflector := string(s.Reflector.Model)
+	if reflector == "" {
+		reflector = string(s.Model.getDefaultReflectorModel())
+	}
+	if s.Reflector.WheelPosition != 0 {
+		reflector += "@" + string(s.Reflector.WheelPosition)
+	}
+	if s.Reflector.Wiring != "" {
+		reflector += "=" + strings.ReplaceAll(s.Reflector.Wiring, " ", "")
+	}
+
+	parts := []string{string(s.Model), reflector, strings.Join(rotorModels, "-"), strings.Join(ringPositions, "-"), string(wheelPositions)}
+	if s.Plugboard != "" {
+		parts = append(parts, s.Plugboard)
+	}
+	return strings.Join(parts, " ")
+}
+
+// settingsDocument is the structured representation of Settings used for JSON and YAML marshalling
+type settingsDocument struct {
+	Model     Model                       `json:"model" yaml:"model"`
+	Rotors    map[RotorSlot]rotorDocument `json:"rotors,omitempty" yaml:"rotors,omitempty"`
+	Reflector *reflectorDocument          `json:"reflector,omitempty" yaml:"reflector,omitempty"`
+	Plugboard []string                    `json:"plugboard,omitempty" yaml:"plugboard,omitempty"`
+}
+
+type rotorDocument struct {
+	Model RotorModel `json:"model" yaml:"model"`
+	Wheel string     `json:"wheel,omitempty" yaml:"wheel,omitempty"`
+	Ring  int        `json:"ring,omitempty" yaml:"ring,omitempty"`
+}
+
+type reflectorDocument struct {
+	Model  ReflectorModel `json:"model,omitempty" yaml:"model,omitempty"`
+	Wheel  string         `json:"wheel,omitempty" yaml:"wheel,omitempty"`
+	Wiring []string       `json:"wiring,omitempty" yaml:"wiring,omitempty"`
+}
+
+func (s Settings) toDocument() settingsDocument {
+	doc := settingsDocument{
+		Model:     s.Model,
+		Plugboard: strings.Fields(s.Plugboard),
+	}
+	if len(s.Rotors) > 0 {
+		doc.Rotors = make(map[RotorSlot]rotorDocument, len(s.Rotors))
+		for slot, config := range s.Rotors {
+			rotorDoc := rotorDocument{Model: config.Model, Ring: config.RingPosition}
+			if config.WheelPosition != 0 {
+				rotorDoc.Wheel = string(config.WheelPosition)
+			}
+			doc.Rotors[slot] = rotorDoc
+		}
+	}
+	if !s.Reflector.isEmpty() {
+		doc.Reflector = &reflectorDocument{
+			Model:  s.Reflector.Model,
+			Wiring: strings.Fields(s.Reflector.Wiring),
+		}
+		if s.Reflector.WheelPosition != 0 {
+			doc.Reflector.Wheel = string(s.Reflector.WheelPosition)
+		}
+	}
+	return doc
+}
+
+func (d settingsDocument) toSettings() (Settings, error) {
+	settings := Settings{
+		Model:     d.Model,
+		Plugboard: strings.Join(d.Plugboard, " "),
+	}
+	if len(d.Rotors) > 0 {
+		settings.Rotors = make(map[RotorSlot]RotorConfig, len(d.Rotors))
+		for slot, rotorDoc := range d.Rotors {
+			config := RotorConfig{Model: rotorDoc.Model, RingPosition: rotorDoc.Ring}
+			if rotorDoc.Wheel != "" {
+				if len(rotorDoc.Wheel) != 1 {
+					return Settings{}, fmt.Errorf("invalid wheel position \"%s\" for rotor %s, must be a single letter", rotorDoc.Wheel, rotorDoc.Model)
+				}
+				config.WheelPosition = rotorDoc.Wheel[0]
+			}
+			settings.Rotors[slot] = config
+		}
+	}
+	if d.Reflector != nil {
+		settings.Reflector = ReflectorConfig{
+			Model:  d.Reflector.Model,
+			Wiring: strings.Join(d.Reflector.Wiring, " "),
+		}
+		if d.Reflector.Wheel != "" {
+			if len(d.Reflector.Wheel) != 1 {
+				return Settings{}, fmt.Errorf("invalid reflector wheel position \"%s\", must be a single letter", d.Reflector.Wheel)
+			}
+			settings.Reflector.WheelPosition = d.Reflector.Wheel[0]
+		}
+	}
+
+	// run the same validation as the Enigma setup does
+	if _, err := NewEnigmaFromSettings(settings); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, the settings are marshalled to the key-sheet shorthand (see String)
+func (s Settings) MarshalText() ([]byte, error) {
+	if !s.Model.exists() {
+		return nil, fmt.Errorf("unsupported model %s", s.Model)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepts the key-sheet shorthand (see ParseSettings)
+func (s *Settings) UnmarshalText(text []byte) error {
+	settings, err := ParseSettings(string(text))
+	if err != nil {
+		return err
+	}
+	*s = settings
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, the settings are marshalled to a structured JSON document
+func (s Settings) MarshalJSON() ([]byte, error) {
+	if !s.Model.exists() {
+		return nil, fmt.Errorf("unsupported model %s", s.Model)
+	}
+	return json.Marshal(s.toDocument())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the unmarshalled settings are validated the same way as the Enigma setup
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	doc := settingsDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	settings, err := doc.toSettings()
+	if err != nil {
+		return err
+	}
+	*s = settings
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface of the common YAML libraries (gopkg.in/yaml.v2 and v3),
+// the settings are marshalled to the same structure as JSON
+func (s Settings) MarshalYAML() (interface{}, error) {
+	if !s.Model.exists() {
+		return nil, fmt.Errorf("unsupported model %s", s.Model)
+	}
+	return s.toDocument(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of the common YAML libraries (gopkg.in/yaml.v2 and v3),
+// the unmarshalled settings are validated the same way as the Enigma setup
+func (s *Settings) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	doc := settingsDocument{}
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	settings, err := doc.toSettings()
+	if err != nil {
+		return err
+	}
+	*s = settings
+	return nil
+}
@@ -0,0 +1,250 @@
+package enigma
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		want     Settings
+	}{
+		{
+			name:     "M3 with plugboard",
+			settings: "M3 B I-IV-III 16-26-08 AJL AT BG DV",
+			want: Settings{
+				Model: M3,
+				Rotors: map[RotorSlot]RotorConfig{
+					Left:   {RotorI, 'A', 16},
+					Middle: {RotorIV, 'J', 26},
+					Right:  {RotorIII, 'L', 8},
+				},
+				Reflector: ReflectorConfig{Model: UkwB},
+				Plugboard: "AT BG DV",
+			},
+		},
+		{
+			name:     "M4 without wheel positions",
+			settings: "M4 CThin gamma-VI-I-VII 18-16-23-02",
+			want: Settings{
+				Model: M4,
+				Rotors: map[RotorSlot]RotorConfig{
+					Fourth: {Model: RotorGamma, RingPosition: 18},
+					Left:   {Model: RotorVI, RingPosition: 16},
+					Middle: {Model: RotorI, RingPosition: 23},
+					Right:  {Model: RotorVII, RingPosition: 2},
+				},
+				Reflector: ReflectorConfig{Model: UkwCThin},
+			},
+		},
+		{
+			name:     "UKW-D wiring",
+			settings: "M4-UKW-D D=AQBGCKDIELFXHZMWNVOTPURS I-II-III 17-05-08 DUZ",
+			want: Settings{
+				Model: M4UKWD,
+				Rotors: map[RotorSlot]RotorConfig{
+					Left:   {RotorI, 'D', 17},
+					Middle: {RotorII, 'U', 5},
+					Right:  {RotorIII, 'Z', 8},
+				},
+				Reflector: ReflectorConfig{Model: UkwD, Wiring: "AQ BG CK DI EL FX HZ MW NV OT PU RS"},
+			},
+		},
+		{
+			name:     "commercial with movable reflector",
+			settings: "Commercial K@Y III-K-I-K-II-K 06-18-04 GZJ",
+			want: Settings{
+				Model: Commercial,
+				Rotors: map[RotorSlot]RotorConfig{
+					Left:   {RotorIIIK, 'G', 6},
+					Middle: {RotorIK, 'Z', 18},
+					Right:  {RotorIIK, 'J', 4},
+				},
+				Reflector: ReflectorConfig{Model: UkwK, WheelPosition: 'Y'},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSettings(tt.settings)
+			if err != nil {
+				t.Errorf("parse error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want = %v\n got = %v", tt.want, got)
+			}
+
+			// formatting must round-trip (missing wheel positions are filled with defaults)
+			reparsed, err := ParseSettings(got.String())
+			if err != nil {
+				t.Errorf("parse error after formatting = %v", err)
+				return
+			}
+			if reparsed.String() != got.String() {
+				t.Errorf("round-trip error\nwant = %v\n got = %v", got.String(), reparsed.String())
+			}
+		})
+	}
+}
+
+func TestParseSettings_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+	}{
+		{name: "too short", settings: "M3 B I-II-III"},
+		{name: "unsupported model", settings: "M5 B I-II-III 01-01-01"},
+		{name: "wrong rotor count", settings: "M3 B I-II 01-01"},
+		{name: "invalid ring position", settings: "M3 B I-II-III 01-XX-01"},
+		{name: "unsupported reflector", settings: "M3 K I-II-III 01-01-01"},
+		{name: "invalid plugboard", settings: "M3 B I-II-III 01-01-01 AAA AB AC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSettings(tt.settings); err == nil {
+				t.Errorf("expected parse error, got none")
+			}
+		})
+	}
+}
+
+func TestSettings_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		want     string
+	}{
+		{"defaults", Settings{Model: M3}, "M3 A III-II-I 01-01-01 AAA"},
+		{"empty", Settings{}, `<unsupported model "">`},
+		{"unknown model", Settings{Model: "M9", Rotors: map[RotorSlot]RotorConfig{Left: {Model: RotorI}}}, `<unsupported model "M9">`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.String(); got != tt.want {
+				t.Errorf("want = %v\n got = %v", tt.want, got)
+			}
+			if _, err := ParseSettings(tt.settings.String()); (err != nil) != (tt.settings.Model != M3) {
+				t.Errorf("unexpected parse result, error = %v", err)
+			}
+		})
+	}
+}
+
+func TestNewEnigmaFromSettings(t *testing.T) {
+	settings, err := ParseSettings("M3 C III-VII-VIII 12-08-06 DUS AI BX CU DF EN GQ HM JL KT OP")
+	if err != nil {
+		t.Errorf("parse error = %v", err)
+		return
+	}
+	e, err := NewEnigmaFromSettings(settings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+
+	// same machine as in the "M3 with full settings" encoding test
+	got, err := e.Encode("LOREMQQIPSUMQQDOLOR")
+	if err != nil {
+		t.Errorf("encode error = %v", err)
+		return
+	}
+	if want := "JRKGDLRCOURDHDKHEEO"; got != want {
+		t.Errorf("want = %v\n got = %v", want, got)
+	}
+}
+
+func TestSettings_JSON(t *testing.T) {
+	settings, err := ParseSettings("M4-UKW-D D=AQBGCKDIELFXHZMWNVOTPURS I-II-III 17-05-08 DUZ AB CD")
+	if err != nil {
+		t.Errorf("parse error = %v", err)
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Errorf("marshal error = %v", err)
+		return
+	}
+	want := `{"model":"M4-UKW-D","rotors":{"left":{"model":"I","wheel":"D","ring":17},"middle":{"model":"II","wheel":"U","ring":5},"right":{"model":"III","wheel":"Z","ring":8}},"reflector":{"model":"D","wiring":["AQ","BG","CK","DI","EL","FX","HZ","MW","NV","OT","PU","RS"]},"plugboard":["AB","CD"]}`
+	if string(data) != want {
+		t.Errorf("want = %v\n got = %v", want, string(data))
+	}
+
+	got := Settings{}
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Errorf("unmarshal error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, settings) {
+		t.Errorf("want = %v\n got = %v", settings, got)
+	}
+
+	text, err := json.Marshal(struct {
+		Settings Settings `json:"settings"`
+	}{settings})
+	if err != nil {
+		t.Errorf("marshal error = %v", err)
+		return
+	}
+	if !strings.Contains(string(text), `"rotors"`) {
+		t.Errorf("nested settings not marshalled as a document: %s", text)
+	}
+}
+
+func TestSettings_MarshalError(t *testing.T) {
+	for _, settings := range []Settings{{}, {Model: "M5"}} {
+		if _, err := json.Marshal(settings); err == nil {
+			t.Errorf("expected JSON marshal error for model %q, got none", settings.Model)
+		}
+		if _, err := settings.MarshalText(); err == nil {
+			t.Errorf("expected text marshal error for model %q, got none", settings.Model)
+		}
+		if _, err := settings.MarshalYAML(); err == nil {
+			t.Errorf("expected YAML marshal error for model %q, got none", settings.Model)
+		}
+	}
+}
+
+func TestSettings_UnmarshalError(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "unsupported model", data: `{"model":"M5"}`},
+		{name: "unsupported slot", data: `{"model":"M3","rotors":{"fifth":{"model":"I"}}}`},
+		{name: "unsupported slot for model", data: `{"model":"M3","rotors":{"fourth":{"model":"beta"}}}`},
+		{name: "invalid wheel position", data: `{"model":"M3","rotors":{"left":{"model":"I","wheel":"AB"}}}`},
+		{name: "invalid ring position", data: `{"model":"M3","rotors":{"left":{"model":"I","ring":27}}}`},
+		{name: "fixed reflector position", data: `{"model":"M3","reflector":{"model":"B","wheel":"C"}}`},
+		{name: "invalid plugboard", data: `{"model":"M3","plugboard":["AB","BC"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := Settings{}
+			if err := json.Unmarshal([]byte(tt.data), &settings); err == nil {
+				t.Errorf("expected unmarshal error, got none")
+			}
+		})
+	}
+}
+
+func TestSettings_Text(t *testing.T) {
+	settings := Settings{}
+	if err := settings.UnmarshalText([]byte("M3 B I-IV-III 16-26-08 AJL AT BG DV")); err != nil {
+		t.Errorf("unmarshal error = %v", err)
+		return
+	}
+	text, err := settings.MarshalText()
+	if err != nil {
+		t.Errorf("marshal error = %v", err)
+		return
+	}
+	if want := "M3 B I-IV-III 16-26-08 AJL AT BG DV"; string(text) != want {
+		t.Errorf("want = %v\n got = %v", want, string(text))
+	}
+}
@@ -0,0 +1,40 @@
+package enigma
+
+import (
+	"fmt"
+)
+
+// State is an opaque snapshot of the running positions of the rotors (and the reflector) of an Enigma machine,
+// see Enigma.State and Enigma.Restore
+type State struct {
+	model             Model
+	rotorPositions    []int
+	reflectorPosition int
+}
+
+// State captures the current wheel positions of all the rotors and of the reflector.
+// Can be used to pause the encoding (ie. when encoding long messages in chunks) and return to the same point later via Restore
+func (e *Enigma) State() State {
+	positions := make([]int, len(e.rotors))
+	for i := range e.rotors {
+		positions[i] = e.rotors[i].getWheelPosition()
+	}
+	return State{
+		model:             e.Model,
+		rotorPositions:    positions,
+		reflectorPosition: e.reflector.wheelPosition,
+	}
+}
+
+// Restore sets the rotors (and the reflector) back to the positions captured by State.
+// Contrary to RotorSetWheel this does not change the starting positions used by RotorsReset
+func (e *Enigma) Restore(state State) error {
+	if state.model != e.Model || len(state.rotorPositions) != len(e.rotors) {
+		return fmt.Errorf("cannot restore state captured on a different Enigma model")
+	}
+	for i, position := range state.rotorPositions {
+		e.rotors[i].wheelPosition = position
+	}
+	e.reflector.wheelPosition = state.reflectorPosition
+	return nil
+}
@@ -0,0 +1,150 @@
+package enigma
+
+import "fmt"
+
+// Stepping is the mechanism advancing the wheels of the machine before each key press
+type Stepping interface {
+	Step(wheels Wheels)
+}
+
+// SteppingFunc allows using an ordinary function as the stepping mechanism (ie. for experimental machines)
+type SteppingFunc func(wheels Wheels)
+
+// Step calls f(wheels)
+func (f SteppingFunc) Step(wheels Wheels) {
+	f(wheels)
+}
+
+// Wheels gives the stepping mechanism access to the rotors and the reflector of the machine.
+// Rotor slots are numbered from the right (the order the current flows through), see GetAvailableRotorSlots
+type Wheels interface {
+	// RotorCount returns the number of rotor slots of the machine
+	RotorCount() int
+	// GetWheel returns the current wheel position of the rotor (letter in the window)
+	GetWheel(slot RotorSlot) byte
+	// IsAtNotch shows if the rotor is on one of its notch positions (about to carry to the next wheel)
+	IsAtNotch(slot RotorSlot) bool
+	// Rotate moves the rotor by one position
+	Rotate(slot RotorSlot)
+	// RotateReflector moves the reflector by one position, fixed reflectors are left in place
+	RotateReflector()
+}
+
+// all built-in stepping mechanisms
+var (
+	// LeverStepping is the ratchet and pawl mechanism of the military Enigma models. The right rotor always steps,
+	// the notch of the middle rotor causes the double step and the fourth rotor never moves
+	LeverStepping Stepping = leverStepping{stators: 0}
+	// GearStepping is the cog wheel mechanism of the Enigma G working like an odometer. There is no double step
+	// and the (movable) reflector is the last wheel of the chain
+	GearStepping Stepping = gearStepping{}
+)
+
+// TypexStepping returns a lever stepping with stators - the given number of rotors (counted from the right) never move
+// and the rotors left of them step the same way as the Enigma rotors. It only borrows the stator idea of the Typex,
+// the Typex itself (five rotors, two of them stators) does not fit the machines with at most four rotor slots.
+// At least one rotor of the machine must be left moving (see Enigma.SetStepping)
+func TypexStepping(stators int) (Stepping, error) {
+	if stators < 0 || stators > int(Fourth) {
+		return nil, fmt.Errorf("invalid number of stators %d, must be between 0 and %d", stators, Fourth)
+	}
+	return leverStepping{stators: stators}, nil
+}
+
+// steppingValidator is implemented by the stepping mechanisms that do not fit every machine
+type steppingValidator interface {
+	validate(rotorCount int) error
+}
+
+// validateStepping checks that the stepping mechanism can drive the machine with the given number of rotor slots
+func validateStepping(stepping Stepping, rotorCount int) error {
+	if validator, ok := stepping.(steppingValidator); ok {
+		return validator.validate(rotorCount)
+	}
+	return nil
+}
+
+type leverStepping struct {
+	stators int
+}
+
+func (s leverStepping) validate(rotorCount int) error {
+	if s.stators >= rotorCount {
+		return fmt.Errorf("invalid number of stators %d, at least one of the %d rotors must move", s.stators, rotorCount)
+	}
+	return nil
+}
+
+func (s leverStepping) Step(wheels Wheels) {
+	first := RotorSlot(s.stators)
+	last := first + 2
+	if count := RotorSlot(wheels.RotorCount()); last >= count {
+		last = count - 1
+	}
+	if first > last {
+		return // nothing to move
+	}
+
+	// the pawls are evaluated before any rotor moves
+	var rotate [Fourth + 1]bool
+	rotate[first] = true // always rotate the fast rotor
+	for slot := first + 1; slot <= last; slot++ {
+		if wheels.IsAtNotch(slot - 1) {
+			rotate[slot] = true
+			rotate[slot-1] = true // double-stepping - the pawl pushes the notched rotor too
+		}
+	}
+	for slot := first; slot <= last; slot++ {
+		if rotate[slot] {
+			wheels.Rotate(slot)
+		}
+	}
+}
+
+type gearStepping struct{}
+
+func (s gearStepping) Step(wheels Wheels) {
+	for slot := RotorSlot(0); int(slot) < wheels.RotorCount(); slot++ {
+		carry := wheels.IsAtNotch(slot)
+		wheels.Rotate(slot)
+		if !carry {
+			return
+		}
+	}
+	wheels.RotateReflector()
+}
+
+// machineWheels exposes the wheels of the Enigma to the stepping mechanisms
+type machineWheels struct {
+	e *Enigma
+}
+
+func (w machineWheels) RotorCount() int {
+	return len(w.e.rotors)
+}
+
+func (w machineWheels) GetWheel(slot RotorSlot) byte {
+	return w.e.alphabet.intToChar(w.getRotor(slot).getWheelPosition())
+}
+
+func (w machineWheels) IsAtNotch(slot RotorSlot) bool {
+	return w.getRotor(slot).shouldRotateNext()
+}
+
+func (w machineWheels) Rotate(slot RotorSlot) {
+	w.getRotor(slot).rotate()
+}
+
+func (w machineWheels) RotateReflector() {
+	if w.e.reflector.model.IsMovable() {
+		w.e.reflector.rotate()
+	}
+}
+
+func (w machineWheels) getRotor(slot RotorSlot) *rotor {
+	index := w.e.rotorSlotToIndex(slot)
+	if index < 0 || index >= len(w.e.rotors) {
+		panic(fmt.Errorf("unsupported rotor slot %d", slot))
+	}
+	return &w.e.rotors[index]
+}
@@ -0,0 +1,126 @@
+package enigma
+
+import (
+	"strings"
+)
+
+// built-in schemes for arbitrary texts
+var (
+	TransliterationScheme Scheme = transliterationScheme{} // letters transliterated to A-Z and digits spelled out, lossy
+	LosslessScheme        Scheme = losslessScheme{}        // escape sequences for everything but the letters, any text round-trips exactly
+)
+
+// transliterationScheme converts the accented Latin letters to their basic form and spells out the digits,
+// spaces and basic punctuation are then handled by the default scheme and anything else is dropped
+type transliterationScheme struct{}
+
+var transliterations = func() map[rune]string {
+	groups := map[string]string{
+		"ÀÁÂÃÄÅĀĂĄ": "A", "Æ": "AE", "ÇĆĈĊČ": "C", "ĎĐÐ": "D", "ÈÉÊËĒĔĖĘĚ": "E", "ĜĞĠĢ": "G", "ĤĦ": "H",
+		"ÌÍÎÏĨĪĬĮİ": "I", "Ĳ": "IJ", "Ĵ": "J", "Ķ": "K", "ĹĻĽĿŁ": "L", "ÑŃŅŇ": "N", "ÒÓÔÕÖØŌŎŐ": "O", "Œ": "OE",
+		"ŔŖŘ": "R", "ŚŜŞŠ": "S", "ẞß": "SS", "ŢŤŦ": "T", "Þ": "TH", "ÙÚÛÜŨŪŬŮŰŲ": "U", "Ŵ": "W", "ÝŸŶ": "Y", "ŹŻŽ": "Z",
+		"0": "ZERO", "1": "ONE", "2": "TWO", "3": "THREE", "4": "FOUR", "5": "FIVE", "6": "SIX", "7": "SEVEN", "8": "EIGHT", "9": "NINE",
+	}
+	result := map[rune]string{}
+	for letters, replacement := range groups {
+		for _, letter := range letters {
+			result[letter] = replacement
+		}
+	}
+	return result
+}()
+
+func (transliterationScheme) Preprocess(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if replacement, ok := transliterations[r]; ok {
+			b.WriteString(replacement)
+		} else if (r >= 'A' && r <= 'Z') || strings.ContainsRune(" .,-", r) {
+			b.WriteRune(r)
+		}
+	}
+	return DefaultScheme.Preprocess(b.String())
+}
+
+func (transliterationScheme) Postprocess(text string) string {
+	return DefaultScheme.Postprocess(text)
+}
+
+// losslessScheme escapes everything but the uppercase letters using Q:
+//   - QQ is the letter Q itself
+//   - QX is a space
+//   - QZ switches between upper and lower case of the following letters
+//   - Q followed by two letters A-P is any other byte in hex (A=0 ... P=15), ie. QCM for "," or QMDQKJ for "é" (two UTF-8 bytes)
+type losslessScheme struct{}
+
+const (
+	escapeLetter = 'Q'
+	escapeSpace  = 'X'
+	escapeCase   = 'Z'
+)
+
+func (losslessScheme) Preprocess(text string) string {
+	var b strings.Builder
+	lower := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		isLower, isUpper := c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z'
+		if (isLower && !lower) || (isUpper && lower) {
+			b.WriteString(string([]byte{escapeLetter, escapeCase}))
+			lower = !lower
+		}
+		switch {
+		case isLower || isUpper:
+			c &^= 'a' - 'A' // to uppercase
+			if c == escapeLetter {
+				b.WriteByte(escapeLetter)
+			}
+			b.WriteByte(c)
+		case c == ' ':
+			b.WriteString(string([]byte{escapeLetter, escapeSpace}))
+		default:
+			b.WriteString(string([]byte{escapeLetter, 'A' + c>>4, 'A' + c&0x0f}))
+		}
+	}
+	return b.String()
+}
+
+func (losslessScheme) Postprocess(text string) string {
+	var b strings.Builder
+	lower := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != escapeLetter || i+1 >= len(text) {
+			if lower && c >= 'A' && c <= 'Z' {
+				c |= 'a' - 'A'
+			}
+			b.WriteByte(c)
+			continue
+		}
+		next := text[i+1]
+		switch {
+		case next == escapeLetter:
+			if lower {
+				c |= 'a' - 'A'
+			}
+			b.WriteByte(c)
+			i++
+		case next == escapeSpace:
+			b.WriteByte(' ')
+			i++
+		case next == escapeCase:
+			lower = !lower
+			i++
+		case i+2 < len(text) && isHexLetter(next) && isHexLetter(text[i+2]):
+			b.WriteByte((next-'A')<<4 | (text[i+2] - 'A'))
+			i += 2
+		default:
+			b.WriteByte(c) // invalid escape sequence, kept as it is
+		}
+	}
+	return b.String()
+}
+
+func isHexLetter(c byte) bool {
+	return c >= 'A' && c <= 'P'
+}
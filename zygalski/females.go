@@ -0,0 +1,50 @@
+package zygalski
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Indicator is the message indicator of the 1938-1940 procedure - ground setting chosen by the operator and sent in clear,
+// followed by the message key enciphered twice at that ground setting
+type Indicator struct {
+	Ground string // 3 letters, left to right
+	Key    string // 6 letters, the doubled enciphered message key
+}
+
+// ParseIndicator parses the indicator from the 9 letters (ground setting followed by the doubled key), spaces are ignored
+func ParseIndicator(indicator string) (Indicator, error) {
+	letters := strings.ReplaceAll(indicator, " ", "")
+	if len(letters) != 9 {
+		return Indicator{}, fmt.Errorf("invalid indicator \"%s\", must be 9 letters long", indicator)
+	}
+	for i := 0; i < len(letters); i++ {
+		if letters[i] < 'A' || letters[i] > 'Z' {
+			return Indicator{}, fmt.Errorf("invalid indicator \"%s\", only uppercase letters A-Z allowed", indicator)
+		}
+	}
+	return Indicator{Ground: letters[:3], Key: letters[3:]}, nil
+}
+
+// Female is an indicator with the same letter on the positions 1 and 4, 2 and 5, or 3 and 6 of the doubled key
+type Female struct {
+	Indicator
+	Pair int // 0 for positions 1/4, 1 for positions 2/5, 2 for positions 3/6
+}
+
+// FindFemales returns all the females in the given indicators (one indicator can contain more of them),
+// the doubled keys of all the indicators must be 6 letters long
+func FindFemales(indicators []Indicator) ([]Female, error) {
+	var females []Female
+	for _, indicator := range indicators {
+		if len(indicator.Key) != 6 {
+			return nil, fmt.Errorf("invalid indicator key \"%s\", must be 6 letters long", indicator.Key)
+		}
+		for pair := 0; pair < 3; pair++ {
+			if indicator.Key[pair] == indicator.Key[pair+3] {
+				females = append(females, Female{Indicator: indicator, Pair: pair})
+			}
+		}
+	}
+	return females, nil
+}
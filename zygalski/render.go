@@ -0,0 +1,69 @@
+package zygalski
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// sheetSize is the number of rows and columns of the printed sheet - same as the original sheets, the 26x26 grid is repeated
+// in both directions so that the sheets can be shifted against each other while still overlapping in a full 26x26 square
+const sheetSize = 2*alphabetSize - 1
+
+var (
+	paperColor = color.RGBA{R: 0xe8, G: 0xdc, B: 0xb8, A: 0xff}
+	holeColor  = color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}
+	lineColor  = color.RGBA{R: 0xb0, G: 0xa0, B: 0x80, A: 0xff}
+)
+
+// WritePNG draws the sheet as a PNG image with the given size of a single cell in pixels (middle rotor positions in rows,
+// right rotor positions in columns), holes are drawn dark
+func (s Sheet) WritePNG(w io.Writer, cellSize int) error {
+	if cellSize < 2 {
+		return fmt.Errorf("invalid cell size %d, must be at least 2 pixels", cellSize)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, sheetSize*cellSize+1, sheetSize*cellSize+1))
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := paperColor
+			if x%cellSize == 0 || y%cellSize == 0 {
+				c = lineColor
+			} else if row, col := y/cellSize, x/cellSize; row < sheetSize && col < sheetSize && s.Holes[row%alphabetSize][col%alphabetSize] {
+				c = holeColor
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// WriteSVG draws the sheet as an SVG image with the rotor order, the left rotor position and the letters of the middle
+// and the right rotor positions along the edges
+func (s Sheet) WriteSVG(w io.Writer) error {
+	const cell, margin = 12, 24
+	size := sheetSize*cell + 2*margin
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"monospace\" font-size=\"9\">\n", size, size, size, size)
+	fmt.Fprintf(out, "<rect width=\"%d\" height=\"%d\" fill=\"#e8dcb8\"/>\n", size, size)
+	fmt.Fprintf(out, "<text x=\"%d\" y=\"10\">%s %c</text>\n", margin, getOrderName(s.RotorOrder), s.Left)
+	for i := 0; i < sheetSize; i++ {
+		letter := 'A' + i%alphabetSize
+		pos := margin + i*cell + cell/2
+		fmt.Fprintf(out, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%c</text>\n", pos, margin-4, letter)
+		fmt.Fprintf(out, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%c</text>\n", margin-8, pos+3, letter)
+	}
+	for row := 0; row < sheetSize; row++ {
+		for col := 0; col < sheetSize; col++ {
+			if s.Holes[row%alphabetSize][col%alphabetSize] {
+				fmt.Fprintf(out, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#202020\"/>\n", margin+col*cell, margin+row*cell, cell, cell)
+			}
+		}
+	}
+	fmt.Fprintf(out, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"none\" stroke=\"#b0a080\"/>\n", margin, margin, sheetSize*cell, sheetSize*cell)
+	fmt.Fprintln(out, "</svg>")
+	return out.Flush()
+}
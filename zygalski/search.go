@@ -0,0 +1,104 @@
+package zygalski
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+)
+
+// Candidate is the rotor order and ring settings surviving the stacking of the sheets
+type Candidate struct {
+	Settings enigma.Settings // rotor order and ring settings, wheel positions are not known
+	Matches  int             // number of females falling through a hole of the stacked sheets
+}
+
+// Search stacks the sheets of every rotor order shifted by the ground settings of the females and returns the ring settings
+// for which at most maxMisses of the females do not fall through a hole, ordered from the most matches.
+// The core position of the female (the sheet and the hole) is its ground setting minus the ring setting, females on the positions
+// 2/5 and 3/6 are the same as females on 1/4 one or two positions of the right rotor later
+func Search(sheets []Sheet, females []Female, maxMisses int) ([]Candidate, error) {
+	if len(females) == 0 {
+		return nil, fmt.Errorf("no females to search for")
+	}
+	for _, female := range females {
+		if len(female.Ground) != 3 || len(female.Key) != 6 || strings.IndexFunc(female.Ground, isNotLetter) != -1 {
+			return nil, fmt.Errorf("invalid female indicator \"%s %s\"", female.Ground, female.Key)
+		}
+	}
+	for i := range sheets {
+		if !sheets[i].Model.HasBasicAlphabet() || isNotLetter(rune(sheets[i].Left)) {
+			return nil, fmt.Errorf("invalid sheet of model %s for the left rotor position %s", sheets[i].Model, string(sheets[i].Left))
+		}
+	}
+
+	// the sheets of one rotor order indexed by the left rotor position
+	var orders []string
+	stacks := map[string]*[alphabetSize]*Sheet{}
+	for i := range sheets {
+		name := getOrderName(sheets[i].RotorOrder)
+		if _, ok := stacks[name]; !ok {
+			stacks[name] = &[alphabetSize]*Sheet{}
+			orders = append(orders, name)
+		}
+		stacks[name][sheets[i].Left-'A'] = &sheets[i]
+	}
+
+	var candidates []Candidate
+	for _, name := range orders {
+		stack := stacks[name]
+		var sample *Sheet
+		for _, sheet := range stack {
+			if sheet == nil {
+				return nil, fmt.Errorf("incomplete sheets for the rotor order %s", name)
+			}
+			sample = sheet
+		}
+		for n := 0; n < alphabetSize*alphabetSize*alphabetSize; n++ {
+			rings := [3]int{n / (alphabetSize * alphabetSize), n / alphabetSize % alphabetSize, n % alphabetSize} // left to right, 0-based
+			matches, misses := 0, 0
+			for _, female := range females {
+				left := shift(female.Ground[0], -rings[0])
+				middle := shift(female.Ground[1], -rings[1])
+				right := shift(female.Ground[2], female.Pair-rings[2])
+				if stack[left].Holes[middle][right] {
+					matches++
+				} else if misses++; misses > maxMisses {
+					break
+				}
+			}
+			if misses > maxMisses {
+				continue
+			}
+			candidates = append(candidates, Candidate{Settings: newSettings(sample, rings), Matches: matches})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Matches > candidates[j].Matches
+	})
+	return candidates, nil
+}
+
+// shift returns the letter position moved by the given offset as an index into the sheet
+func shift(letter byte, offset int) int {
+	return ((int(letter-'A')+offset)%alphabetSize + alphabetSize) % alphabetSize
+}
+
+func newSettings(sheet *Sheet, rings [3]int) enigma.Settings {
+	slots := []enigma.RotorSlot{enigma.Left, enigma.Middle, enigma.Right}
+	rotors := make(map[enigma.RotorSlot]enigma.RotorConfig, len(slots))
+	for i, slot := range slots {
+		rotors[slot] = enigma.RotorConfig{Model: sheet.RotorOrder[slot], RingPosition: rings[i] + 1}
+	}
+	return enigma.Settings{
+		Model:     sheet.Model,
+		Rotors:    rotors,
+		Reflector: enigma.ReflectorConfig{Model: sheet.Reflector},
+	}
+}
+
+func isNotLetter(letter rune) bool {
+	return letter < 'A' || letter > 'Z'
+}
@@ -0,0 +1,149 @@
+// Package zygalski implements the Zygalski sheets method of breaking the Enigma traffic with doubled indicators
+// enciphered at ground settings chosen by the operators (1938-1940 procedure).
+//
+// A "female" is an indicator where the same letter appears on positions 1 and 4 (or 2 and 5, 3 and 6) of the doubled key.
+// This can only happen at machine positions where the AD permutation has a fixed point, which does not depend on the plugboard.
+// Zygalski sheets mark all such positions for every rotor order, and stacking the sheets shifted by the ground settings
+// of the females reveals the ring settings. Same as the original sheets, the method ignores the middle rotor turnovers
+// caused by the unknown ring settings, so a few females can be missed and the search allows for that.
+package zygalski
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/toolkit"
+	"github.com/tomas-hanicinec/enigma/rejewski"
+)
+
+const alphabetSize = enigma.AlphabetSize
+
+// Config specifies the machine and the rotor orders for generating the sheets
+type Config struct {
+	Model       enigma.Model
+	Reflector   enigma.ReflectorModel                    // default reflector of the model if empty
+	RotorOrders []map[enigma.RotorSlot]enigma.RotorModel // all rotor orders supported by the model if empty
+	Workers     int                                      // number of CPUs if empty
+}
+
+// Sheet is the Zygalski sheet for a single rotor order and left rotor position, marking the positions of the middle
+// and the right rotor where a female can occur (all the rings on the first position)
+type Sheet struct {
+	Model      enigma.Model
+	Reflector  enigma.ReflectorModel
+	RotorOrder map[enigma.RotorSlot]enigma.RotorModel
+	Left       byte
+	Holes      [alphabetSize][alphabetSize]bool // [middle][right] position
+}
+
+// NewSheets generates the sheets (26 for every rotor order, one for each left rotor position) by stepping a real Enigma
+func NewSheets(config Config) ([]Sheet, error) {
+	if err := config.Model.RequireBasicAlphabet(); err != nil {
+		return nil, err
+	}
+	if len(config.Model.GetAvailableRotorSlots()) != 3 {
+		return nil, fmt.Errorf("zygalski sheets only supported for 3-rotor models")
+	}
+	if config.Reflector == "" {
+		e, err := enigma.NewEnigma(config.Model)
+		if err != nil {
+			return nil, err
+		}
+		config.Reflector = e.GetReflectorModel()
+	}
+	if len(config.RotorOrders) == 0 {
+		config.RotorOrders = config.Model.GetRotorOrders()
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	machines := make([]enigma.Enigma, len(config.RotorOrders))
+	for i, order := range config.RotorOrders {
+		rotors := make(map[enigma.RotorSlot]enigma.RotorConfig, len(order))
+		for slot, model := range order {
+			rotors[slot] = enigma.RotorConfig{Model: model}
+		}
+		e, err := enigma.NewEnigmaWithSetup(config.Model, rotors, enigma.ReflectorConfig{Model: config.Reflector}, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid sheets configuration: %w", err)
+		}
+		machines[i] = e
+	}
+
+	sheets := make([]Sheet, len(config.RotorOrders)*alphabetSize)
+	toolkit.RunParallel(config.Workers, len(sheets), func(i int) {
+		e := machines[i/alphabetSize].Clone()
+		sheets[i] = newSheet(&e, config.RotorOrders[i/alphabetSize], byte('A'+i%alphabetSize))
+		sheets[i].Model, sheets[i].Reflector = config.Model, config.Reflector
+	})
+
+	return sheets, nil
+}
+
+func newSheet(e *enigma.Enigma, order map[enigma.RotorSlot]enigma.RotorModel, left byte) Sheet {
+	sheet := Sheet{RotorOrder: order, Left: left}
+	if err := e.RotorSetWheel(enigma.Left, left); err != nil {
+		panic(fmt.Errorf("failed to set wheel position: %w", err))
+	}
+	for middle := 0; middle < alphabetSize; middle++ {
+		for right := 0; right < alphabetSize; right++ {
+			setCorePosition(e, middle, right)
+			ad, _, _, err := rejewski.GetPermutations(e)
+			if err != nil {
+				panic(fmt.Errorf("failed to compute permutations: %w", err))
+			}
+			sheet.Holes[middle][right] = hasFixedPoint(ad)
+		}
+	}
+	return sheet
+}
+
+// setCorePosition sets the middle and the right rotor to the given core positions (wheel position relative to the ring),
+// choosing the rings so that the middle rotor does not move while enciphering the doubled key. Only the right rotor
+// moves on the original sheets, females with a turnover in their indicator are simply missed
+func setCorePosition(e *enigma.Enigma, middle, right int) {
+	for middleRing := 1; middleRing <= alphabetSize; middleRing++ {
+		for rightRing := 1; rightRing <= alphabetSize; rightRing++ {
+			setRotor(e, enigma.Middle, middle, middleRing)
+			setRotor(e, enigma.Right, right, rightRing)
+			start := e.State()
+			wheel, _ := e.GetRotorWheel(enigma.Middle)
+			if _, err := e.Encode("AAAAAA"); err != nil {
+				panic(fmt.Errorf("failed to encode: %w", err))
+			}
+			moved, _ := e.GetRotorWheel(enigma.Middle)
+			if err := e.Restore(start); err != nil {
+				panic(fmt.Errorf("failed to restore position: %w", err))
+			}
+			if moved == wheel {
+				return
+			}
+		}
+	}
+	panic(fmt.Errorf("no ring settings without the middle rotor turnover")) // rotors have at most two notches
+}
+
+func setRotor(e *enigma.Enigma, slot enigma.RotorSlot, core, ring int) {
+	if err := e.RotorSetRing(slot, ring); err != nil {
+		panic(fmt.Errorf("failed to set ring position: %w", err))
+	}
+	if err := e.RotorSetWheel(slot, byte('A'+(core+ring-1)%alphabetSize)); err != nil {
+		panic(fmt.Errorf("failed to set wheel position: %w", err))
+	}
+}
+
+func hasFixedPoint(p rejewski.Permutation) bool {
+	for i, mapped := range p {
+		if i == mapped {
+			return true
+		}
+	}
+	return false
+}
+
+// getOrderName returns the rotor order in the key-sheet notation (left to right), ie. "II-I-III"
+func getOrderName(order map[enigma.RotorSlot]enigma.RotorModel) string {
+	return strings.Join([]string{string(order[enigma.Left]), string(order[enigma.Middle]), string(order[enigma.Right])}, "-")
+}
@@ -0,0 +1,179 @@
+package zygalski
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/tomas-hanicinec/enigma"
+	"github.com/tomas-hanicinec/enigma/internal/enigmatest"
+)
+
+func TestFindFemales(t *testing.T) {
+	var indicators []Indicator
+	for _, s := range []string{"RTJ WAHWIK", "ABC DEFGHI", "XYZ ABCABC"} {
+		indicator, err := ParseIndicator(s)
+		if err != nil {
+			t.Errorf("parse error = %v", err)
+			return
+		}
+		indicators = append(indicators, indicator)
+	}
+	females, err := FindFemales(indicators)
+	if err != nil {
+		t.Errorf("find error = %v", err)
+		return
+	}
+	if len(females) != 4 || females[0].Ground != "RTJ" || females[0].Pair != 0 || females[3].Pair != 2 {
+		t.Errorf("invalid females %v", females)
+	}
+
+	for _, s := range []string{"ABCDEFGH", "ABC DEFGHIJ", "abc defghi"} {
+		if _, err := ParseIndicator(s); err == nil {
+			t.Errorf("expected error for indicator %s, got none", s)
+		}
+	}
+	for _, key := range []string{"", "WAHWI", "WAHWIKX"} {
+		if _, err = FindFemales([]Indicator{{Ground: "RTJ", Key: key}}); err == nil {
+			t.Errorf("expected error for key %q, got none", key)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	daySettings, err := enigma.ParseSettings("I A II-I-III 16-04-21 AB CD EF GH IJ KL")
+	if err != nil {
+		t.Errorf("settings error = %v", err)
+		return
+	}
+	e, err := enigma.NewEnigmaFromSettings(daySettings)
+	if err != nil {
+		t.Errorf("config error = %v", err)
+		return
+	}
+
+	// traffic of a single day - random message keys enciphered twice at random ground settings
+	random := rand.New(rand.NewSource(1))
+	randomLetters := func(n int) string {
+		letters := make([]byte, n)
+		for i := range letters {
+			letters[i] = byte('A' + random.Intn(26))
+		}
+		return string(letters)
+	}
+	var indicators []Indicator
+	for i := 0; i < 1000; i++ {
+		ground := randomLetters(3)
+		for j, slot := range []enigma.RotorSlot{enigma.Left, enigma.Middle, enigma.Right} {
+			if err = e.RotorSetWheel(slot, ground[j]); err != nil {
+				t.Errorf("wheel error = %v", err)
+				return
+			}
+		}
+		key, err := e.Encode(strings.Repeat(randomLetters(3), 2))
+		if err != nil {
+			t.Errorf("encode error = %v", err)
+			return
+		}
+		indicators = append(indicators, Indicator{Ground: ground, Key: key})
+	}
+	females, err := FindFemales(indicators)
+	if err != nil {
+		t.Errorf("find error = %v", err)
+		return
+	}
+	if len(females) < 20 {
+		t.Errorf("not enough females for the test (%d)", len(females))
+		return
+	}
+
+	sheets, err := NewSheets(Config{
+		Model:     enigma.One,
+		Reflector: enigma.UkwA,
+		RotorOrders: []map[enigma.RotorSlot]enigma.RotorModel{
+			{enigma.Left: enigma.RotorI, enigma.Middle: enigma.RotorII, enigma.Right: enigma.RotorIII},
+			{enigma.Left: enigma.RotorII, enigma.Middle: enigma.RotorI, enigma.Right: enigma.RotorIII},
+		},
+	})
+	if err != nil {
+		t.Errorf("sheets error = %v", err)
+		return
+	}
+	if len(sheets) != 2*26 {
+		t.Errorf("want = %v\n got = %v", 2*26, len(sheets))
+	}
+
+	// females with the middle rotor turnover in the indicator are not recorded on the sheets
+	candidates, err := Search(sheets, females, len(females)/5)
+	if err != nil {
+		t.Errorf("search error = %v", err)
+		return
+	}
+	if len(candidates) == 0 {
+		t.Errorf("no candidates found for %d females", len(females))
+		return
+	}
+	if got, want := candidates[0].Settings.String(), "I A II-I-III 16-04-21 AAA"; got != want {
+		t.Errorf("want = %v\n got = %v (%d candidates)", want, got, len(candidates))
+	}
+
+	if _, err = Search(sheets, nil, 0); err == nil {
+		t.Errorf("expected error for no females, got none")
+	}
+}
+
+func TestNewSheets_Alphabet(t *testing.T) {
+	numeric := enigmatest.RegisterNumeric()
+	if _, err := NewSheets(Config{Model: numeric}); !errors.Is(err, enigma.ErrUnsupportedAlphabet) {
+		t.Errorf("want = %v\n got = %v", enigma.ErrUnsupportedAlphabet, err)
+	}
+	females := []Female{{Indicator: Indicator{Ground: "123", Key: "456456"}}}
+	if _, err := Search([]Sheet{{Model: numeric, Left: '0'}}, females, 0); err == nil {
+		t.Errorf("expected invalid female error, got none")
+	}
+	females[0].Ground = "ABC"
+	if _, err := Search([]Sheet{{Model: numeric, Left: '0'}}, females, 0); err == nil {
+		t.Errorf("expected invalid sheet error, got none")
+	}
+}
+
+func TestSheet_Write(t *testing.T) {
+	sheet := Sheet{
+		RotorOrder: map[enigma.RotorSlot]enigma.RotorModel{enigma.Left: enigma.RotorI, enigma.Middle: enigma.RotorII, enigma.Right: enigma.RotorIII},
+		Left:       'C',
+	}
+	sheet.Holes[1][2] = true
+
+	var buf bytes.Buffer
+	if err := sheet.WritePNG(&buf, 4); err != nil {
+		t.Errorf("png error = %v", err)
+		return
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Errorf("png decode error = %v", err)
+		return
+	}
+	if size := img.Bounds().Dx(); size != 51*4+1 {
+		t.Errorf("want = %v\n got = %v", 51*4+1, size)
+	}
+	if r, _, _, _ := img.At(2*4+2, 1*4+2).RGBA(); r>>8 != 0x20 {
+		t.Errorf("hole not drawn dark")
+	}
+	if err = sheet.WritePNG(&buf, 1); err == nil {
+		t.Errorf("expected cell size error, got none")
+	}
+
+	buf.Reset()
+	if err = sheet.WriteSVG(&buf); err != nil {
+		t.Errorf("svg error = %v", err)
+		return
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "I-II-III C") || strings.Count(svg, "fill=\"#202020\"") != 4 {
+		t.Errorf("invalid svg output")
+	}
+}